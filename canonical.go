@@ -0,0 +1,338 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Canonical returns the canonical encoding of the struct: a byte encoding
+// where the fields are sorted by their keys and every value is prefixed with
+// its kind, so equal structs are encoded equally across processes and Go
+// versions. Nil pointers, slices and maps are distinguished from empty and
+// zero values. The "-" and "omitempty" options are respected as in Map, and a
+// tag value with the option of "nohash" leaves the field out. Example:
+//
+//	// Field is not part of the encoding
+//	UpdatedAt time.Time `structs:"updated_at,nohash"`
+//
+// Types without exported fields, such as time.Time, are encoded with their
+// MarshalText or MarshalBinary method. It returns an error for the values
+// which cannot be encoded, such as channels, functions and cycles.
+func (s *Struct) Canonical() ([]byte, error) {
+	e := &canonicalEncoder{visiting: make(map[visit]bool)}
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr {
+		e.visiting[visit{ptr: v.Pointer(), typ: v.Type()}] = true
+	}
+
+	// a struct and a pointer to it are encoded equally
+	if err := e.encode(s, s.value, ""); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// Hash writes the canonical encoding of the struct to the given hash and
+// returns its sum. For more info refer to Canonical. Example:
+//
+//	sum, err := structs.New(v).Hash(sha256.New())
+func (s *Struct) Hash(h hash.Hash) ([]byte, error) {
+	data, err := s.Canonical()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.Write(data); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+// canonicalEncoder writes the canonical encoding of a value
+type canonicalEncoder struct {
+	buf      bytes.Buffer
+	visiting map[visit]bool
+}
+
+// kind prefixes of the canonical encoding
+const (
+	canonicalNil     = 'n'
+	canonicalTrue    = 't'
+	canonicalFalse   = 'f'
+	canonicalInt     = 'i'
+	canonicalUint    = 'u'
+	canonicalFloat   = 'd'
+	canonicalComplex = 'c'
+	canonicalString  = 's'
+	canonicalBytes   = 'b'
+	canonicalList    = 'l'
+	canonicalMap     = 'm'
+	canonicalStruct  = 'S'
+	canonicalPointer = 'p'
+	canonicalText    = 'x'
+)
+
+// writeUint writes the given integer as 8 big endian bytes
+func (e *canonicalEncoder) writeUint(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf.Write(b[:])
+}
+
+// writeBytes writes the given bytes prefixed with their length
+func (e *canonicalEncoder) writeBytes(kind byte, b []byte) {
+	e.buf.WriteByte(kind)
+	e.writeUint(uint64(len(b)))
+	e.buf.Write(b)
+}
+
+// writeFloat writes the given float, with a single encoding for NaN and zero
+func (e *canonicalEncoder) writeFloat(f float64) {
+	switch {
+	case math.IsNaN(f):
+		f = math.NaN()
+	case f == 0:
+		f = 0
+	}
+	e.writeUint(math.Float64bits(f))
+}
+
+// encode writes the given value found at the given path. The Struct s holds
+// the options the struct fields are read with.
+func (e *canonicalEncoder) encode(s *Struct, val reflect.Value, path string) error {
+	if !val.IsValid() {
+		e.buf.WriteByte(canonicalNil)
+		return nil
+	}
+
+	if mapper, ok := asMapper(val); ok {
+		value, err := mapper.StructsMap()
+		if err != nil {
+			return fmt.Errorf("%v:(%s)", err, refPath(path))
+		}
+		return e.encode(s, reflect.ValueOf(value), path)
+	}
+
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			e.buf.WriteByte(canonicalNil)
+			return nil
+		}
+
+		if val.Kind() == reflect.Ptr {
+			key := visit{ptr: val.Pointer(), typ: val.Type()}
+			if e.visiting[key] {
+				return fmt.Errorf("%w:(%s)", errCycle, refPath(path))
+			}
+
+			e.visiting[key] = true
+			defer delete(e.visiting, key)
+			e.buf.WriteByte(canonicalPointer)
+		}
+
+		return e.encode(s, val.Elem(), path)
+	case reflect.Bool:
+		if val.Bool() {
+			e.buf.WriteByte(canonicalTrue)
+		} else {
+			e.buf.WriteByte(canonicalFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.buf.WriteByte(canonicalInt)
+		e.writeUint(uint64(val.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.buf.WriteByte(canonicalUint)
+		e.writeUint(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.buf.WriteByte(canonicalFloat)
+		e.writeFloat(val.Float())
+	case reflect.Complex64, reflect.Complex128:
+		e.buf.WriteByte(canonicalComplex)
+		e.writeFloat(real(val.Complex()))
+		e.writeFloat(imag(val.Complex()))
+	case reflect.String:
+		e.writeBytes(canonicalString, []byte(val.String()))
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			e.buf.WriteByte(canonicalNil)
+			return nil
+		}
+
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, val.Len())
+			reflect.Copy(reflect.ValueOf(b), val)
+			e.writeBytes(canonicalBytes, b)
+			return nil
+		}
+
+		e.buf.WriteByte(canonicalList)
+		e.writeUint(uint64(val.Len()))
+		for i := 0; i < val.Len(); i++ {
+			if err := e.encode(s, val.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if val.IsNil() {
+			e.buf.WriteByte(canonicalNil)
+			return nil
+		}
+
+		return e.encodeMap(s, val, path)
+	case reflect.Struct:
+		return e.encodeStruct(s, val, path)
+	default:
+		return fmt.Errorf("type %s is not supported:(%s)", val.Type(), refPath(path))
+	}
+
+	return nil
+}
+
+// encodeMap writes the entries of the given map sorted by the encoding of
+// their keys
+func (e *canonicalEncoder) encodeMap(s *Struct, val reflect.Value, path string) error {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+		path  string
+	}
+
+	entries := make([]entry, 0, val.Len())
+	for _, k := range val.MapKeys() {
+		keyPath := joinPath(path, mapKey(k))
+
+		ke := &canonicalEncoder{visiting: e.visiting}
+		if err := ke.encode(s, k, keyPath); err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: ke.buf.Bytes(), value: val.MapIndex(k), path: keyPath})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	e.buf.WriteByte(canonicalMap)
+	e.writeUint(uint64(len(entries)))
+	for _, entry := range entries {
+		e.buf.Write(entry.key)
+		if err := e.encode(s, entry.value, entry.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeStruct writes the fields of the given struct sorted by their keys
+func (e *canonicalEncoder) encodeStruct(s *Struct, val reflect.Value, path string) error {
+	if !hasExportedFields(val.Type()) {
+		return e.encodeOpaque(val, path)
+	}
+
+	sub := s.sub(val.Interface(), path)
+
+	type field struct {
+		key   string
+		value reflect.Value
+	}
+
+	var fields []field
+	for _, f := range sub.structFields() {
+		value, ok := sub.fieldValue(f)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			continue
+		}
+
+		key, tagOpts := sub.fieldKey(f)
+		if tagOpts.Has("nohash") {
+			continue
+		}
+
+		if (tagOpts.Has("omitempty") || s.config.omitEmptyAll) && isEmptyValue(value) {
+			continue
+		}
+
+		if tagOpts.Has("omitzero") && isZeroValue(value) {
+			continue
+		}
+
+		fields = append(fields, field{key: key, value: value})
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+
+	e.buf.WriteByte(canonicalStruct)
+	e.writeUint(uint64(len(fields)))
+	for _, f := range fields {
+		e.writeBytes(canonicalString, []byte(f.key))
+		if err := e.encode(sub, f.value, joinPath(path, f.key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeOpaque writes a struct without exported fields, ie: time.Time, with
+// its MarshalText or MarshalBinary method
+func (e *canonicalEncoder) encodeOpaque(val reflect.Value, path string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch v := val.Interface().(type) {
+	case encoding.TextMarshaler:
+		data, err = v.MarshalText()
+	case encoding.BinaryMarshaler:
+		data, err = v.MarshalBinary()
+	default:
+		if val.NumField() > 0 {
+			return fmt.Errorf("type %s is not supported:(%s)", val.Type(), refPath(path))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v:(%s)", err, refPath(path))
+	}
+
+	e.writeBytes(canonicalText, data)
+	return nil
+}
@@ -0,0 +1,149 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func hashHex(t *testing.T, v any) string {
+	t.Helper()
+
+	sum, err := Hash(v, sha256.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(sum)
+}
+
+func TestHash(t *testing.T) {
+	type Item struct {
+		SKU   string  `structs:"sku"`
+		Price float64 `structs:"price"`
+	}
+
+	type Order struct {
+		ID        string            `structs:"id"`
+		Items     []Item            `structs:"items"`
+		Labels    map[string]string `structs:"labels"`
+		Notes     []string          `structs:"notes"`
+		Created   time.Time         `structs:"created"`
+		Comment   string            `structs:"comment,omitempty"`
+		UpdatedAt time.Time         `structs:"updated_at,nohash"`
+		Ignored   string            `structs:"-"`
+	}
+
+	newOrder := func() Order {
+		labels := make(map[string]string)
+		for i := 0; i < 20; i++ {
+			labels[fmt.Sprintf("key%d", i)] = fmt.Sprint(i)
+		}
+
+		return Order{
+			ID:      "o-1",
+			Items:   []Item{{SKU: "a", Price: 1.5}, {SKU: "b", Price: 2}},
+			Labels:  labels,
+			Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+
+	o := newOrder()
+	expected := hashHex(t, o)
+
+	for i := 0; i < 10; i++ {
+		if sum := hashHex(t, newOrder()); sum != expected {
+			t.Fatalf("Equal structs should hash equally, got: %s and %s", expected, sum)
+		}
+	}
+
+	if sum := hashHex(t, &o); sum != expected {
+		t.Errorf("A struct and a pointer to it should hash equally, got: %s and %s", expected, sum)
+	}
+
+	o.UpdatedAt = time.Now()
+	o.Ignored = "ignored"
+	if sum := hashHex(t, o); sum != expected {
+		t.Errorf("The nohash and ignored fields should not change the hash, got: %s", sum)
+	}
+
+	o.Notes = []string{}
+	if sum := hashHex(t, o); sum == expected {
+		t.Error("An empty slice should not hash like a nil slice")
+	}
+
+	o = newOrder()
+	o.Items[1].Price = 2.01
+	if sum := hashHex(t, o); sum == expected {
+		t.Error("Different structs should not hash equally")
+	}
+}
+
+func TestCanonical_Golden(t *testing.T) {
+	type Item struct {
+		SKU   string  `structs:"sku"`
+		Price float64 `structs:"price"`
+	}
+
+	// the canonical encoding must not change across versions
+	data, err := Canonical(Item{SKU: "a", Price: 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "5300000000000000027300000000000000057072696365643ff8000000000000" +
+		"730000000000000003736b7573000000000000000161"
+	if got := hex.EncodeToString(data); got != expected {
+		t.Errorf("Canonical should be %s, got: %s", expected, got)
+	}
+}
+
+func TestHash_Errors(t *testing.T) {
+	type Node struct {
+		ID     string `structs:"id"`
+		Parent *Node  `structs:"parent"`
+	}
+
+	n := &Node{ID: "n-1"}
+	n.Parent = n
+
+	_, err := Hash(n, sha256.New())
+	if !errors.Is(err, errCycle) {
+		t.Errorf("Hash should return %v, got: %v", errCycle, err)
+	}
+
+	type T struct {
+		C chan int
+	}
+
+	if _, err := Hash(T{C: make(chan int)}, sha256.New()); err == nil {
+		t.Error("Hash should return an error for a channel")
+	}
+}
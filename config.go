@@ -0,0 +1,343 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"hash"
+	"log/slog"
+)
+
+// Option configures a Config
+type Option func(*Config)
+
+// Config holds the settings used to convert structs to maps and to fill
+// structs from maps. A Config is immutable once created, hence it is safe to
+// share it across goroutines. Example:
+//
+//	var config = structs.NewConfig(structs.WithTagName("json"), structs.WithNaming(structs.SnakeCase))
+//
+//	m := config.Map(server)
+type Config struct {
+	tagName           string
+	fallbackTagNames  []string
+	omitEmptyAll      bool
+	naming            NamingStrategy
+	matchPolicy       MatchPolicy
+	normalizers       []KeyNormalizer
+	cyclePolicy       CyclePolicy
+	collisionPolicy   CollisionPolicy
+	defaultGroups     []string
+	protection        ProtectionPolicy
+	sensitivePatterns []string
+	redactor          Redactor
+	maxDepth          int
+	promoteEmbedded   bool
+	keepMapKeys       bool
+	deep              bool
+}
+
+// NewConfig creates a Config with the given options. The tag name defaults to
+// DefaultTagName at the time NewConfig is called.
+func NewConfig(opts ...Option) *Config {
+	config := &Config{
+		tagName: DefaultTagName,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// WithTagName sets the tag name of the struct fields
+func WithTagName(tagName string) Option {
+	return func(config *Config) {
+		config.tagName = tagName
+	}
+}
+
+// WithFallbackTagNames sets the tag names looked up, in order, for the fields
+// that do not have a tag for the tag name, ie: "json". The options of the
+// "json", "yaml" and "mapstructure" tags are understood.
+func WithFallbackTagNames(tagNames ...string) Option {
+	return func(config *Config) {
+		config.fallbackTagNames = append([]string(nil), tagNames...)
+	}
+}
+
+// WithOmitEmptyAll handles every field as if it had the "omitempty" option
+func WithOmitEmptyAll() Option {
+	return func(config *Config) {
+		config.omitEmptyAll = true
+	}
+}
+
+// WithNaming sets the naming strategy converting the name of the fields
+// without a tag name into their key, ie: SnakeCase
+func WithNaming(naming NamingStrategy) Option {
+	return func(config *Config) {
+		config.naming = naming
+	}
+}
+
+// WithMatchPolicy sets how the map keys are matched against the field names
+// when filling a struct. It defaults to MatchExact.
+func WithMatchPolicy(policy MatchPolicy) Option {
+	return func(config *Config) {
+		config.matchPolicy = policy
+	}
+}
+
+// WithKeyNormalizers sets the normalizers rewriting, in order, the map keys
+// and the field names before they are matched against each other when
+// filling a struct
+func WithKeyNormalizers(normalizers ...KeyNormalizer) Option {
+	return func(config *Config) {
+		config.normalizers = append([]KeyNormalizer(nil), normalizers...)
+	}
+}
+
+// WithCyclePolicy sets how a struct referencing one of its ancestors through
+// a pointer is converted. It defaults to CycleError.
+func WithCyclePolicy(policy CyclePolicy) Option {
+	return func(config *Config) {
+		config.cyclePolicy = policy
+	}
+}
+
+// WithCollisionPolicy sets how the fields written under the same key, usually
+// by a struct with the "flatten" option, are converted. It defaults to
+// CollisionOverwrite.
+func WithCollisionPolicy(policy CollisionPolicy) Option {
+	return func(config *Config) {
+		config.collisionPolicy = policy
+	}
+}
+
+// WithDefaultGroups sets the groups of the fields without a "groups" tag
+// option, which belong to every group by default. For more info refer to
+// Struct types ForGroups() method.
+func WithDefaultGroups(groups ...string) Option {
+	return func(config *Config) {
+		config.defaultGroups = append([]string{}, groups...)
+	}
+}
+
+// WithProtectionPolicy sets how FillStruct handles the keys of the map which
+// target a "readonly" field or a field left out with Only or Except. It
+// defaults to ProtectionIgnore.
+func WithProtectionPolicy(policy ProtectionPolicy) Option {
+	return func(config *Config) {
+		config.protection = policy
+	}
+}
+
+// WithSensitivePatterns marks as sensitive the fields whose lowercased name or
+// key matches one of the given path.Match patterns, ie: "*password*" or
+// "*token". For more info refer to Struct types Redacted() method.
+func WithSensitivePatterns(patterns ...string) Option {
+	return func(config *Config) {
+		config.sensitivePatterns = append([]string{}, patterns...)
+	}
+}
+
+// WithRedactor sets the Redactor replacing the values of the sensitive fields
+// in the output of Redacted. It defaults to Mask.
+func WithRedactor(redactor Redactor) Option {
+	return func(config *Config) {
+		config.redactor = redactor
+	}
+}
+
+// WithMaxDepth limits the number of nested struct levels converted into
+// maps. The structs nested deeper are kept as they are. Zero means no limit.
+func WithMaxDepth(depth int) Option {
+	return func(config *Config) {
+		config.maxDepth = depth
+	}
+}
+
+// WithEmbeddedPromotion promotes the fields of the embedded structs, or
+// pointer to structs, the way encoding/json does, in Map, Values, Names and
+// FillStruct. An embedded struct without a tag name no longer appears under
+// its type name. Its fields appear as if they were declared by the embedding
+// struct unless a less nested field, or an equally nested field with a tag
+// name, has the same key. Equally nested fields sharing a key without a
+// winner are left out. The fields of a nil embedded pointer are left out too.
+//
+// FillStruct then fills the nested structs which are not embedded from a
+// nested map, just like a pointer to struct.
+func WithEmbeddedPromotion() Option {
+	return func(config *Config) {
+		config.promoteEmbedded = true
+	}
+}
+
+// WithMapKeyTypes keeps the type of the keys of the maps holding structs
+// which are not strings. Such maps are converted to a map[any]any instead of
+// a map[string]any with formatted keys.
+func WithMapKeyTypes() Option {
+	return func(config *Config) {
+		config.keepMapKeys = true
+	}
+}
+
+// WithDeepConversion converts every struct held by the slices, arrays and
+// maps of a struct, at any depth, into maps. The dynamic type of the values
+// held by interfaces is inspected, ie: the Foo held by []any{Foo{}} is
+// converted as well. By default only the slices and maps whose element type
+// is a struct are converted.
+func WithDeepConversion() Option {
+	return func(config *Config) {
+		config.deep = true
+	}
+}
+
+// New returns a new *Struct with the struct s using the settings of the
+// Config. It panics if the s's kind is not struct.
+func (c *Config) New(s any) *Struct {
+	return &Struct{
+		raw:     s,
+		value:   structVal(s),
+		TagName: c.tagName,
+		config:  c,
+	}
+}
+
+// Map converts the given struct to a map[string]any. For more info refer to
+// Struct types Map() method. It panics if s's kind is not struct.
+func (c *Config) Map(s any) map[string]any {
+	return c.New(s).Map()
+}
+
+// Entries returns the entries of the given struct in the fields declaration
+// order. For more info refer to Struct types Entries() method. It panics if
+// s's kind is not struct.
+func (c *Config) Entries(s any) []Entry {
+	return c.New(s).Entries()
+}
+
+// Ordered converts the given struct to an *OrderedMap. For more info refer to
+// Struct types Ordered() method. It panics if s's kind is not struct.
+func (c *Config) Ordered(s any) *OrderedMap {
+	return c.New(s).Ordered()
+}
+
+// MapErr converts the given struct to a map[string]any and returns the error
+// reported by a Mapper or by the CycleError or CollisionError policies. For
+// more info refer to Struct types MapErr() method. It panics if s's kind is not
+// struct.
+func (c *Config) MapErr(s any) (map[string]any, error) {
+	return c.New(s).MapErr()
+}
+
+// EntriesErr returns the entries of the given struct and the error reported by
+// a Mapper or by the CycleError or CollisionError policies. For more info refer
+// to Struct types EntriesErr() method. It panics if s's kind is not struct.
+func (c *Config) EntriesErr(s any) ([]Entry, error) {
+	return c.New(s).EntriesErr()
+}
+
+// OrderedErr converts the given struct to an *OrderedMap and returns the error
+// reported by a Mapper or by the CycleError or CollisionError policies. For
+// more info refer to Struct types OrderedErr() method. It panics if s's kind is
+// not struct.
+func (c *Config) OrderedErr(s any) (*OrderedMap, error) {
+	return c.New(s).OrderedErr()
+}
+
+// ValuesErr converts the given struct to a []any and returns the error reported
+// by a Mapper or by the CycleError policy. For more info refer to Struct types
+// ValuesErr() method. It panics if s's kind is not struct.
+func (c *Config) ValuesErr(s any) ([]any, error) {
+	return c.New(s).ValuesErr()
+}
+
+// Values converts the given struct to a []any. For more info refer to Struct
+// types Values() method. It panics if s's kind is not struct.
+func (c *Config) Values(s any) []any {
+	return c.New(s).Values()
+}
+
+// Names returns a slice of field names. For more info refer to Struct types
+// Names() method. It panics if s's kind is not struct.
+func (c *Config) Names(s any) []string {
+	return c.New(s).Names()
+}
+
+// Fields returns a slice of *Field. For more info refer to Struct types
+// Fields() method. It panics if s's kind is not struct.
+func (c *Config) Fields(s any) []*Field {
+	return c.New(s).Fields()
+}
+
+// FillStruct fills the given struct with the provided map in place. For more
+// info refer to Struct types FillStruct() method. It panics if s's kind is
+// not struct.
+func (c *Config) FillStruct(m map[string]any, s any) (DecodeMetadata, error) {
+	return c.New(s).FillStruct(m)
+}
+
+// Reset zeroes every field of the given struct. For more info refer to Struct
+// types Reset() method. It panics if s's kind is not struct.
+func (c *Config) Reset(s any, opts ...ResetOption) error {
+	return c.New(s).Reset(opts...)
+}
+
+// Redacted returns a map of the given struct with the values of its sensitive
+// fields redacted. For more info refer to Struct types Redacted() method. It
+// panics if s's kind is not struct.
+func (c *Config) Redacted(s any) map[string]any {
+	return c.New(s).Redacted()
+}
+
+// Redact returns a deep copy of the given struct with the values of its
+// sensitive fields zeroed. For more info refer to Struct types Redact()
+// method. It panics if s's kind is not struct.
+func (c *Config) Redact(s any) any {
+	return c.New(s).Redact()
+}
+
+// LogValue returns the slog.Value of the given struct. For more info refer to
+// Struct types LogValue() method. It panics if s's kind is not struct.
+func (c *Config) LogValue(s any) slog.Value {
+	return c.New(s).LogValue()
+}
+
+// Canonical returns the canonical encoding of the given struct. For more info
+// refer to Struct types Canonical() method. It panics if s's kind is not
+// struct.
+func (c *Config) Canonical(s any) ([]byte, error) {
+	return c.New(s).Canonical()
+}
+
+// Hash writes the canonical encoding of the given struct to the given hash
+// and returns its sum. For more info refer to Struct types Canonical() method.
+// It panics if s's kind is not struct.
+func (c *Config) Hash(s any, h hash.Hash) ([]byte, error) {
+	return c.New(s).Hash(h)
+}
@@ -0,0 +1,138 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNew_Options(t *testing.T) {
+	type Server struct {
+		Name    string `json:"name"`
+		ID      int    `json:"id"`
+		Enabled bool
+	}
+
+	server := &Server{Name: "gopher", ID: 42}
+
+	s := New(server, WithTagName("json"), WithNaming(SnakeCase), WithOmitEmptyAll())
+	if s.TagName != "json" {
+		t.Errorf("TagName should be json, got: %s", s.TagName)
+	}
+
+	expected := map[string]any{"name": "gopher", "id": 42}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	if v := s.Values(); !reflect.DeepEqual(v, []any{"gopher", 42}) {
+		t.Errorf("Values should omit the empty fields, got: %+v", v)
+	}
+}
+
+func TestNewConfig_DefaultTagName(t *testing.T) {
+	config := NewConfig()
+	if config.tagName != DefaultTagName {
+		t.Errorf("Tag name should default to %s, got: %s", DefaultTagName, config.tagName)
+	}
+
+	defer func(tagName string) { DefaultTagName = tagName }(DefaultTagName)
+	DefaultTagName = "json"
+
+	type Server struct {
+		Name string `json:"name"`
+	}
+
+	m := config.Map(&Server{Name: "gopher"})
+	if _, ok := m["Name"]; !ok {
+		t.Errorf("A Config should not be affected by a later change of DefaultTagName, got: %+v", m)
+	}
+}
+
+func TestConfig_Concurrent(t *testing.T) {
+	type Server struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+	}
+
+	config := NewConfig(WithTagName("json"), WithMaxDepth(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			in := &Server{Name: "gopher", ID: id}
+			m := config.Map(in)
+
+			out := &Server{}
+			if _, err := config.FillStruct(m, out); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if out.Name != in.Name || out.ID != in.ID {
+				t.Errorf("Struct should be %+v, got: %+v", in, out)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestConfig_Methods(t *testing.T) {
+	type Server struct {
+		Name    string `json:"name"`
+		ID      int    `json:"id"`
+		Enabled bool
+	}
+
+	config := NewConfig(WithNaming(KebabCase))
+	server := &Server{Name: "gopher", ID: 42, Enabled: true}
+
+	if n := config.Names(server); !reflect.DeepEqual(n, []string{"name", "id", "enabled"}) {
+		t.Errorf("Names should use the naming strategy, got: %v", n)
+	}
+
+	if v := config.Values(server); !reflect.DeepEqual(v, []any{"gopher", 42, true}) {
+		t.Errorf("Values should be [gopher 42 true], got: %v", v)
+	}
+
+	if f := config.Fields(server); len(f) != 3 {
+		t.Errorf("Fields should return 3 fields, got: %d", len(f))
+	}
+
+	if e := config.Entries(server); len(e) != 3 || e[2].Key != "enabled" {
+		t.Errorf("Entries should use the naming strategy, got: %+v", e)
+	}
+
+	if o := config.Ordered(server); !reflect.DeepEqual(o.Keys(), []string{"name", "id", "enabled"}) {
+		t.Errorf("Ordered should use the naming strategy, got: %v", o.Keys())
+	}
+}
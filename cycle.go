@@ -0,0 +1,154 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errCycle = errors.New("cycle detected")
+
+// CyclePolicy defines how a struct referencing one of its ancestors through a
+// pointer is converted
+type CyclePolicy int
+
+const (
+	// CycleError reports an error naming both ends of the cycle. MapErr,
+	// EntriesErr, OrderedErr and ValuesErr return it while the other methods
+	// panic with it.
+	CycleError CyclePolicy = iota
+	// CycleSkip leaves the field referencing an ancestor out of the output
+	CycleSkip
+	// CycleRef replaces the struct referencing an ancestor with a placeholder
+	// map holding the path of that ancestor under the "$ref" key, ie:
+	// {"$ref": "$.Parent"}. The root struct path is "$".
+	CycleRef
+)
+
+// visit identifies a struct pointer being converted. The type is part of it
+// since a struct and its first field share the same address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// MapErr is the same as Map but returns the error reported by a Mapper or by
+// the CycleError or CollisionError policies rather than panicking with it
+func (s *Struct) MapErr() (m map[string]any, err error) {
+	defer recoverError(&err)
+	return s.Map(), nil
+}
+
+// EntriesErr is the same as Entries but returns the error reported by a Mapper
+// or by the CycleError or CollisionError policies rather than panicking with it
+func (s *Struct) EntriesErr() (entries []Entry, err error) {
+	defer recoverError(&err)
+	return s.Entries(), nil
+}
+
+// OrderedErr is the same as Ordered but returns the error reported by a Mapper
+// or by the CycleError or CollisionError policies rather than panicking with it
+func (s *Struct) OrderedErr() (m *OrderedMap, err error) {
+	defer recoverError(&err)
+	return s.Ordered(), nil
+}
+
+// ValuesErr is the same as Values but returns the error reported by a Mapper or
+// by the CycleError policy rather than panicking with it
+func (s *Struct) ValuesErr() (values []any, err error) {
+	defer recoverError(&err)
+	return s.Values(), nil
+}
+
+// recoverError sets the given error from a panic raised with an error
+// reported by a Mapper or a policy, any other panic goes on
+func recoverError(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if e, ok := r.(error); ok && (errors.Is(e, errCycle) || errors.Is(e, errKeyCollision) || errors.Is(e, errMapper)) {
+		*err = e
+		return
+	}
+
+	panic(r)
+}
+
+// walk returns a copy of s tracking the pointers visited by a new conversion,
+// so that s can be shared
+func (s *Struct) walk() *Struct {
+	n := *s
+	n.visited = make(map[visit]string)
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr {
+		n.visited[visit{ptr: v.Pointer(), typ: v.Type()}] = s.path
+	}
+
+	return &n
+}
+
+// enter marks the struct v found at the given path as visited until the
+// returned func is called, when v is a pointer. The boolean returns false
+// along with the path of the ancestor v references when it is one of them.
+func (s *Struct) enter(v any, path string) (func(), string, bool) {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return func() {}, "", true
+	}
+
+	key := visit{ptr: ptr.Pointer(), typ: ptr.Type()}
+	if ancestor, ok := s.visited[key]; ok {
+		return nil, ancestor, false
+	}
+
+	s.visited[key] = path
+	return func() { delete(s.visited, key) }, "", true
+}
+
+// cycle returns the replacement of the struct found at the given path that
+// references the ancestor found at the ancestor path. The boolean returns
+// false when the struct should be left out of the output.
+func (s *Struct) cycle(path, ancestor string) (any, []Entry, bool) {
+	switch s.config.cyclePolicy {
+	case CycleSkip:
+		return nil, nil, false
+	case CycleRef:
+		return map[string]any{"$ref": refPath(ancestor)}, nil, true
+	default:
+		panic(fmt.Errorf("%w: %s references %s", errCycle, refPath(path), refPath(ancestor)))
+	}
+}
+
+// refPath returns the given path rooted at "$"
+func refPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return "$." + path
+}
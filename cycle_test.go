@@ -0,0 +1,261 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_CycleError(t *testing.T) {
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, errCycle) {
+			t.Fatalf("A cycle should panic with %v, got: %v", errCycle, err)
+		}
+
+		expected := "cycle detected: $.Children[0].Parent references $"
+		if err.Error() != expected {
+			t.Errorf("Error should be %q, got: %q", expected, err.Error())
+		}
+	}()
+
+	_ = Map(root)
+}
+
+func TestMap_CycleSkip(t *testing.T) {
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	s := New(root, WithCyclePolicy(CycleSkip))
+
+	expected := map[string]any{
+		"Name":   "root",
+		"Parent": (*Node)(nil),
+		"Children": []any{
+			map[string]any{
+				"Name":     "child",
+				"Children": []any{},
+			},
+		},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+}
+
+func TestMap_CycleRef(t *testing.T) {
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	s := New(root, WithCyclePolicy(CycleRef))
+
+	m := s.Map()
+	child := m["Children"].([]any)[0].(map[string]any)
+
+	expected := map[string]any{"$ref": "$"}
+	if !reflect.DeepEqual(child["Parent"], expected) {
+		t.Errorf("Parent should be %+v, got: %+v", expected, child["Parent"])
+	}
+}
+
+func TestMap_SharedPointer(t *testing.T) {
+	type Leaf struct {
+		Name string
+	}
+
+	type Pair struct {
+		Left  *Leaf
+		Right *Leaf
+	}
+
+	leaf := &Leaf{Name: "leaf"}
+	m := Map(&Pair{Left: leaf, Right: leaf})
+
+	expected := map[string]any{
+		"Left":  map[string]any{"Name": "leaf"},
+		"Right": map[string]any{"Name": "leaf"},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("A pointer shared by siblings is not a cycle, expected %+v, got: %+v", expected, m)
+	}
+}
+
+func TestMap_MaxDepth(t *testing.T) {
+	type C struct {
+		Name string
+	}
+
+	type B struct {
+		C C
+	}
+
+	type A struct {
+		B B
+	}
+
+	a := A{B: B{C: C{Name: "deep"}}}
+
+	s := New(a, WithMaxDepth(1))
+
+	expected := map[string]any{
+		"B": map[string]any{
+			"C": C{Name: "deep"},
+		},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	// the depth limit also stops the conversion of cycles
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	tree := New(root, WithMaxDepth(1))
+
+	m := tree.Map()
+	child := m["Children"].([]any)[0].(map[string]any)
+	if _, ok := child["Parent"].(*Node); !ok {
+		t.Errorf("Parent should be kept as is beyond the maximum depth, got: %T", child["Parent"])
+	}
+}
+
+func TestMapErr_Cycle(t *testing.T) {
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	m, err := MapErr(root)
+	if !errors.Is(err, errCycle) {
+		t.Fatalf("A cycle should return %v, got: %v", errCycle, err)
+	}
+
+	if m != nil {
+		t.Errorf("Map should be nil in case of error, got: %+v", m)
+	}
+
+	if _, err := NewConfig(WithCyclePolicy(CycleSkip)).MapErr(root); err != nil {
+		t.Errorf("A skipped cycle should not return an error, got: %v", err)
+	}
+
+	type A struct {
+		Name string
+	}
+
+	if _, err := EntriesErr(&A{Name: "a"}); err != nil {
+		t.Errorf("A struct without cycle should not return an error, got: %v", err)
+	}
+}
+
+func TestValues_Cycle(t *testing.T) {
+	type Ring struct {
+		Name string
+		Next *Ring
+	}
+
+	a := &Ring{Name: "a"}
+	a.Next = &Ring{Name: "b", Next: a}
+
+	if _, err := ValuesErr(a); !errors.Is(err, errCycle) {
+		t.Errorf("A cycle should return %v, got: %v", errCycle, err)
+	}
+
+	values := New(a, WithCyclePolicy(CycleSkip)).Values()
+	expected := []any{"a", "b"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Values should be %v, got: %v", expected, values)
+	}
+
+	values = New(a, WithCyclePolicy(CycleRef)).Values()
+	expected = []any{"a", "b", map[string]any{"$ref": "$"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Values should be %v, got: %v", expected, values)
+	}
+}
+
+func TestIsZero_Cycle(t *testing.T) {
+	type Ring struct {
+		Name string
+		Next *Ring
+	}
+
+	a := &Ring{}
+	a.Next = &Ring{Next: a}
+
+	if !IsZero(a) {
+		t.Error("IsZero should be true for a cycle of empty structs")
+	}
+
+	if !HasZero(a) {
+		t.Error("HasZero should be true for a cycle of empty structs")
+	}
+
+	a.Name, a.Next.Name = "a", "b"
+
+	if IsZero(a) {
+		t.Error("IsZero should be false for a cycle of set structs")
+	}
+
+	if HasZero(a) {
+		t.Error("HasZero should be false for a cycle of set structs")
+	}
+}
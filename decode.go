@@ -0,0 +1,814 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errNotAddressable = errors.New("struct is not addressable")
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// DecodeMetadata reports how a map has been applied to a struct by FillStruct.
+// Keys are reported using their position in the input map, ie: "Parent.Child"
+// for a nested map and "Items[0].Name" for a slice element. Fields are reported
+// using their Go path in the struct, ie: "Address.City".
+type DecodeMetadata struct {
+	// Keys contains the map keys that have been consumed by a field
+	Keys []string
+	// Unused contains the map keys that did not match any field
+	Unused []string
+	// Unset contains the fields that have been left untouched because
+	// no key matched them
+	Unset []string
+	// Defaults contains the fields that have been set from the value of
+	// their "default" tag option
+	Defaults []string
+}
+
+// MatchPolicy defines how the keys of a map are matched against the field
+// names when filling a struct
+type MatchPolicy int
+
+const (
+	// MatchExact matches keys that are equal to the field name
+	MatchExact MatchPolicy = iota
+	// MatchCaseInsensitive matches keys that are equal to the field name
+	// regardless of their case, ie: "userid" matches "UserID"
+	MatchCaseInsensitive
+)
+
+// KeyNormalizer rewrites a map key or a field name before they are matched
+// against each other
+type KeyNormalizer func(key string) string
+
+// RemoveSeparators is a KeyNormalizer that removes the "_", "-" and " "
+// separators of a key. Combined with MatchCaseInsensitive "user_id", "userId"
+// and "UserID" all match the same field.
+func RemoveSeparators(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', ' ':
+			return -1
+		default:
+			return r
+		}
+	}, key)
+}
+
+// decoder fills structs from maps and records what has been applied
+type decoder struct {
+	tagNames    []string
+	policy      MatchPolicy
+	normalizers []KeyNormalizer
+	naming      NamingStrategy
+	promote     bool
+	selection   selection
+	protection  ProtectionPolicy
+	meta        *DecodeMetadata
+}
+
+// claims records which field of a struct consumed each key of the input map,
+// so that a key matching several fields is reported rather than applied to all
+// of them
+type claims struct {
+	fields map[string]string
+	// names holds the normalized keys and aliases of the fields, which a Go
+	// field name only falls back to when no field uses them
+	names map[string]bool
+}
+
+// newClaims creates the claims of the given fields, whose keys are prefixed by
+// the given prefix
+func (d *decoder) newClaims(fields []reflect.StructField, prefix string) *claims {
+	c := &claims{
+		fields: make(map[string]string, len(fields)),
+		names:  make(map[string]bool, len(fields)),
+	}
+
+	for _, field := range fields {
+		names, _ := d.fieldNames(field, prefix)
+		for _, name := range names {
+			c.names[d.normalize(name)] = true
+		}
+	}
+
+	return c
+}
+
+// fieldNames returns the names the given field is looked up by: its key, then
+// its aliases, prefixed by the given prefix
+func (d *decoder) fieldNames(field reflect.StructField, prefix string) ([]string, TagOptions) {
+	primary, tagOpts := fieldKey(field, d.tagNames, d.naming)
+	names := []string{prefix + primary}
+	if aliases, ok := tagOpts.Get("alias"); ok {
+		for _, alias := range strings.Split(aliases, "|") {
+			names = append(names, prefix+alias)
+		}
+	}
+
+	return names, tagOpts
+}
+
+// selectField returns a copy of d filling the children of the field with the
+// given name and key. The boolean returns false when the field is left out.
+func (d *decoder) selectField(name, key string) (*decoder, bool) {
+	if d.selection == nil {
+		return d, true
+	}
+
+	sel, ok := d.selection.field(name, key)
+	if !ok {
+		return nil, false
+	}
+
+	n := *d
+	n.selection = sel
+	return &n, true
+}
+
+// selectElement returns a copy of d filling the slice element or map entry
+// with the given index or key. The boolean returns false when it is left out.
+func (d *decoder) selectElement(key string) (*decoder, bool) {
+	if d.selection == nil {
+		return d, true
+	}
+
+	sel, ok := d.selection.element(key)
+	if !ok {
+		return nil, false
+	}
+
+	n := *d
+	n.selection = sel
+	return &n, true
+}
+
+// normalize returns the form of the given key used for matching
+func (d *decoder) normalize(key string) string {
+	for _, normalizer := range d.normalizers {
+		key = normalizer(key)
+	}
+
+	if d.policy == MatchCaseInsensitive {
+		key = strings.ToLower(key)
+	}
+
+	return key
+}
+
+// index groups the keys of the input map by their normalized form
+func (d *decoder) index(input reflect.Value) map[string][]reflect.Value {
+	keys := input.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+
+	index := make(map[string][]reflect.Value, len(keys))
+	for _, key := range keys {
+		name := d.normalize(fmt.Sprint(key.Interface()))
+		index[name] = append(index[name], key)
+	}
+
+	return index
+}
+
+// lookup returns the keys of the input map matching any of the given names
+func (d *decoder) lookup(index map[string][]reflect.Value, names []string) []reflect.Value {
+	var (
+		keys []reflect.Value
+		seen = make(map[string]bool)
+	)
+
+	for _, name := range names {
+		name = d.normalize(name)
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		keys = append(keys, index[name]...)
+	}
+
+	return keys
+}
+
+// fromPtr sets the given output from the value of a pointer
+func (d *decoder) fromPtr(in any, t reflect.Type, out reflect.Value, keyPath, fieldPath string) error {
+	child := reflect.New(t.Elem())
+	if err := d.fromValue(in, child.Elem(), child.Elem().Type(), keyPath, fieldPath); err != nil {
+		return err
+	}
+	out.Set(child)
+	return nil
+}
+
+// fromSlice sets the given output from a given the elements of a slice
+func (d *decoder) fromSlice(in any, out reflect.Value, t reflect.Type, keyPath, fieldPath string) (err error) {
+	input := reflect.ValueOf(in)
+	if input.Kind() != reflect.Slice {
+		return errNotSlice
+	}
+
+	output := reflect.MakeSlice(t, input.Len(), input.Cap())
+	for i := 0; i < input.Len(); i++ {
+		ed, ok := d.selectElement(strconv.Itoa(i))
+		if !ok {
+			continue
+		}
+
+		inputValue := reflect.ValueOf(input.Index(i).Interface())
+		elem := reflect.New(output.Index(i).Type()).Elem()
+		index := fmt.Sprintf("[%d]", i)
+		if e := ed.fromValue(inputValue.Interface(), elem, elem.Type(), keyPath+index, fieldPath+index); e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+
+		output.Index(i).Set(elem)
+	}
+
+	if err == nil {
+		out.Set(output)
+	}
+
+	return
+}
+
+// fromMap sets the given output from a given the elements of a map
+func (d *decoder) fromMap(in any, out reflect.Value, t reflect.Type, keyPath, fieldPath string) (err error) {
+	input := reflect.ValueOf(in)
+	if input.Kind() != reflect.Map {
+		return errNotMap
+	}
+
+	output := reflect.MakeMap(t)
+	for _, key := range input.MapKeys() {
+		value := reflect.ValueOf(key.Interface())
+		iface := value.Interface()
+		outKey := reflect.New(t.Key()).Elem()
+		if str, ok := iface.(string); ok && isTextKey(t.Key()) {
+			// keys formatted by Map are parsed back
+			parsed, e := parseKey(str, t.Key())
+			if e != nil {
+				err = errors.Join(err, fmt.Errorf("%v:(%s)", e, str))
+				continue
+			}
+			outKey.Set(parsed)
+		} else if e := d.fromValue(iface, outKey, outKey.Type(), keyPath, fieldPath); e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+
+		ed, ok := d.selectElement(fmt.Sprint(iface))
+		if !ok {
+			continue
+		}
+
+		inputValue := reflect.ValueOf(input.MapIndex(value).Interface()).Interface()
+		outputValue := reflect.New(output.Type().Elem()).Elem()
+		index := fmt.Sprintf("[%v]", iface)
+		if e := ed.fromValue(inputValue, outputValue, outputValue.Type(), joinPath(keyPath, fmt.Sprint(iface)), fieldPath+index); e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+
+		output.SetMapIndex(outKey, outputValue)
+	}
+
+	if err == nil {
+		// Special case: out may be a struct or struct pointer...
+		out.Set(output)
+	}
+
+	return
+}
+
+// fromArray sets the given output from a given array or slice elements
+func (d *decoder) fromArray(in any, out reflect.Value, t reflect.Type, keyPath, fieldPath string) (err error) {
+	input := reflect.ValueOf(in)
+	if input.Kind() != reflect.Array && input.Kind() != reflect.Slice {
+		return errNotArrayOrSlice
+	}
+
+	output := reflect.New(t).Elem()
+	for i := 0; i < input.Len(); i++ {
+		ed, ok := d.selectElement(strconv.Itoa(i))
+		if !ok {
+			continue
+		}
+
+		outputValue := output.Index(i)
+		inputValue := input.Index(i)
+		index := fmt.Sprintf("[%d]", i)
+		if e := ed.fromValue(inputValue.Interface(), outputValue, outputValue.Type(), keyPath+index, fieldPath+index); e != nil {
+			err = errors.Join(err, fmt.Errorf("%v:(%s)", e, fmt.Sprintf("@%d", i)))
+			continue
+		}
+	}
+
+	if err == nil {
+		out.Set(output)
+	}
+
+	return
+}
+
+// fromValue set the value of a given input from a given reflected value
+func (d *decoder) fromValue(in any, out reflect.Value, t reflect.Type, keyPath, fieldPath string) error {
+	// structs of the same type, ie: time.Time, are copied over
+	if out.Kind() == reflect.Struct && reflect.TypeOf(in) == t {
+		out.Set(reflect.ValueOf(in))
+		return nil
+	}
+
+	if filler, ok := asFiller(out); ok {
+		return filler.StructsFill(in)
+	}
+
+	switch out.Kind() {
+	case reflect.Ptr:
+		return d.fromPtr(in, t, out, keyPath, fieldPath)
+	case reflect.Struct:
+		return d.toStruct(in, out, keyPath, fieldPath)
+	case reflect.Slice:
+		return d.fromSlice(in, out, t, keyPath, fieldPath)
+	case reflect.Map:
+		return d.fromMap(in, out, t, keyPath, fieldPath)
+	case reflect.Array:
+		return d.fromArray(in, out, t, keyPath, fieldPath)
+	default:
+		// pass
+	}
+
+	inputValue := reflect.ValueOf(in)
+	inputType := inputValue.Type()
+	outputType := reflect.ValueOf(out.Interface()).Type()
+
+	if inputType == outputType {
+		// default case: copy the value over
+		out.Set(reflect.ValueOf(in))
+		return nil
+	}
+
+	if inputType.AssignableTo(outputType) {
+		// types are assignable
+		out.Set(inputValue)
+		return nil
+	}
+
+	if inputType.ConvertibleTo(outputType) {
+		// types are convertible
+		out.Set(inputValue.Convert(outputType))
+		return nil
+	}
+
+	return fmt.Errorf("type mismatch: %s and %s are incompatible", outputType.String(), inputType.String())
+}
+
+// toStruct fills a given struct with the provided map values and records the
+// keys of the map that have not been consumed by any field
+func (d *decoder) toStruct(in any, s reflect.Value, keyPath, fieldPath string) error {
+	if in == nil {
+		return errors.New("input data is nil")
+	}
+
+	// make sure input is a map
+	input := reflect.ValueOf(in)
+	if input.Kind() != reflect.Map {
+		return errNotMap
+	}
+
+	consumed := make(map[string]bool, input.Len())
+	err := d.fillStruct(input, d.index(input), s, consumed, keyPath, fieldPath, "")
+
+	var unused []string
+	for _, key := range input.MapKeys() {
+		name := fmt.Sprint(key.Interface())
+		if !consumed[name] {
+			unused = append(unused, joinPath(keyPath, name))
+		}
+	}
+
+	sort.Strings(unused)
+	d.meta.Unused = append(d.meta.Unused, unused...)
+	return err
+}
+
+// fillStruct fills a given struct with the values of the input map. Embedded
+// and value structs are filled from the same input map, with their keys
+// prefixed by the given prefix and the "prefix" option of a flattened field.
+func (d *decoder) fillStruct(input reflect.Value, index map[string][]reflect.Value, s reflect.Value, consumed map[string]bool, keyPath, fieldPath, prefix string) (err error) {
+	// if target is a pointer to a struct: create a new instance
+	if s.Kind() == reflect.Ptr {
+		s.Set(reflect.New(s.Type().Elem()))
+		s = s.Elem()
+	}
+
+	if s.Kind() != reflect.Struct {
+		return errNotStruct
+	}
+
+	if d.promote {
+		return d.fillPromoted(input, index, s, consumed, keyPath, fieldPath, prefix)
+	}
+
+	// get the all the exported fields of th passed struct
+	fields := getFields(s, d.tagNames)
+
+	structFields := make([]reflect.StructField, len(fields))
+	for i, field := range fields {
+		structFields[i] = field.field
+	}
+	claimed := d.newClaims(structFields, prefix)
+
+	// Hold the values of the modified fields in a map, by their index in the
+	// struct, which will be applied shortly before this function returns.
+	// This ensures we do not modify the target struct at all in case of an error
+	modifiedFields := make(map[int]reflect.Value, len(fields))
+	for _, field := range fields {
+		name := field.Name()
+		val := s.FieldByName(name)
+		path := joinPath(fieldPath, name)
+
+		key, tagOpts := fieldKey(field.field, d.tagNames, d.naming)
+		fd, ok := d.selectField(name, key)
+		if !ok || tagOpts.Has("readonly") {
+			if e := d.protect(index, claimed, field.field, path, prefix); e != nil {
+				err = errors.Join(err, e)
+			}
+			continue
+		}
+
+		if field.IsEmbedded() {
+			if e := fd.fillStruct(input, index, val, consumed, keyPath, path, flatPrefix(field.field, d.tagNames, prefix)); e != nil {
+				err = errors.Join(err, e)
+				continue
+			}
+			continue
+		}
+		// ignore unexported field
+		if !field.IsExported() {
+			continue
+		}
+
+		// handle value struct, unless it fills itself
+		if field.Kind() == reflect.Struct && !reflect.PointerTo(val.Type()).Implements(fillerType) {
+			if e := fd.fillStruct(input, index, val, consumed, keyPath, path, flatPrefix(field.field, d.tagNames, prefix)); e != nil {
+				err = errors.Join(err, e)
+				continue
+			}
+			continue
+		}
+
+		// flattened pointers are only allocated when a key targets them
+		if field.Kind() == reflect.Ptr && isFlattened(field.field, d.tagNames) {
+			if !d.targeted(index, claimed, field.field, prefix) {
+				d.meta.Unset = append(d.meta.Unset, path)
+				continue
+			}
+
+			elem := reflect.New(val.Type()).Elem()
+			if e := fd.fillStruct(input, index, elem, consumed, keyPath, path, flatPrefix(field.field, d.tagNames, prefix)); e != nil {
+				err = errors.Join(err, e)
+				continue
+			}
+
+			modifiedFields[field.field.Index[0]] = elem
+			continue
+		}
+
+		// interfaces are not supported
+		if field.Kind() == reflect.Interface {
+			err = errors.Join(err, fmt.Errorf("interface not supported:(%s)", name))
+			continue
+		}
+
+		elem, ok, e := fd.decodeField(input, index, consumed, claimed, field.field, keyPath, path, prefix)
+		if e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+
+		if ok {
+			modifiedFields[field.field.Index[0]] = elem
+		}
+	}
+
+	// Apply changes to all modified fields in case no error happened during processing.
+	if err == nil {
+		// Apply changes to all modified fields
+		for index, value := range modifiedFields {
+			s.Field(index).Set(value)
+		}
+	}
+	return
+}
+
+// isTextKey returns true when the keys of the given type are written as
+// strings which differ from their value by Map
+func isTextKey(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	return t.Kind() != reflect.String && t.Kind() != reflect.Interface && isScalar(t)
+}
+
+// parseKey converts the string form of a map key written by Map into a key of
+// the given type
+func parseKey(key string, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t)
+	if unmarshaler, ok := out.Interface().(encoding.TextUnmarshaler); ok {
+		err := unmarshaler.UnmarshalText([]byte(key))
+		return out.Elem(), err
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// integers are formatted with strconv, even a time.Duration
+		i, err := strconv.ParseInt(key, 10, t.Bits())
+		if err != nil {
+			return out.Elem(), err
+		}
+		out.Elem().SetInt(i)
+		return out.Elem(), nil
+	default:
+		return parseString(key, t)
+	}
+}
+
+// isScalar returns true when the given type, or the type it points to, is a
+// boolean, a number or a string
+func isScalar(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// fillPromoted fills a given struct with the values of the input map the way
+// encoding/json does: the fields of embedded structs are promoted to the
+// struct and nested structs are filled from a nested map.
+func (d *decoder) fillPromoted(input reflect.Value, index map[string][]reflect.Value, s reflect.Value, consumed map[string]bool, keyPath, fieldPath, prefix string) (err error) {
+	type change struct {
+		index []int
+		value reflect.Value
+	}
+
+	var changes []change
+	fields := promotedFields(s.Type(), d.tagNames, d.naming)
+	claimed := d.newClaims(fields, prefix)
+	for _, field := range fields {
+		path := joinPath(fieldPath, indexPath(s.Type(), field.Index))
+
+		key, tagOpts := fieldKey(field, d.tagNames, d.naming)
+		fd, ok := d.selectField(field.Name, key)
+		if !ok || tagOpts.Has("readonly") {
+			if e := d.protect(index, claimed, field, path, prefix); e != nil {
+				err = errors.Join(err, e)
+			}
+			continue
+		}
+
+		// interfaces are not supported
+		if field.Type.Kind() == reflect.Interface {
+			err = errors.Join(err, fmt.Errorf("interface not supported:(%s)", field.Name))
+			continue
+		}
+
+		if _, e := fieldByIndex(s, field.Index, true); e != nil {
+			err = errors.Join(err, fmt.Errorf("%v:(%s)", e, path))
+			continue
+		}
+
+		// flattened structs are filled from the same input map, pointers only
+		// when a key targets them
+		if isFlattened(field, d.tagNames) {
+			if field.Type.Kind() == reflect.Ptr && !d.targeted(index, claimed, field, prefix) {
+				d.meta.Unset = append(d.meta.Unset, path)
+				continue
+			}
+
+			elem := reflect.New(field.Type).Elem()
+			if current, _ := fieldByIndex(s, field.Index, true); current.IsValid() {
+				elem.Set(current)
+			}
+
+			if e := fd.fillStruct(input, index, elem, consumed, keyPath, path, flatPrefix(field, d.tagNames, prefix)); e != nil {
+				err = errors.Join(err, e)
+				continue
+			}
+
+			changes = append(changes, change{index: field.Index, value: elem})
+			continue
+		}
+
+		elem, ok, e := fd.decodeField(input, index, consumed, claimed, field, keyPath, path, prefix)
+		if e != nil {
+			err = errors.Join(err, e)
+			continue
+		}
+
+		if ok {
+			changes = append(changes, change{index: field.Index, value: elem})
+		}
+	}
+
+	// Apply changes to all modified fields in case no error happened during processing.
+	if err == nil {
+		for _, c := range changes {
+			value, _ := fieldByIndex(s, c.index, false)
+			value.Set(c.value)
+		}
+	}
+	return
+}
+
+// fieldKeys returns the keys of the input map matching the given field: its
+// key and aliases, else its Go field name unless another field of the struct
+// uses it
+func (d *decoder) fieldKeys(index map[string][]reflect.Value, claimed *claims, field reflect.StructField, prefix string) ([]reflect.Value, TagOptions) {
+	names, tagOpts := d.fieldNames(field, prefix)
+	keys := d.lookup(index, names)
+	if fallback := prefix + field.Name; len(keys) == 0 && !claimed.names[d.normalize(fallback)] {
+		keys = d.lookup(index, []string{fallback})
+	}
+
+	return keys, tagOpts
+}
+
+// decodeField returns the value of the given field from the input map found at
+// the given key path. The boolean returns false when the field has been left
+// unset. The keys are looked up by fieldKeys.
+func (d *decoder) decodeField(input reflect.Value, index map[string][]reflect.Value, consumed map[string]bool, claimed *claims, field reflect.StructField, keyPath, path, prefix string) (reflect.Value, bool, error) {
+	name := field.Name
+	fieldType := field.Type
+
+	keys, tagOpts := d.fieldKeys(index, claimed, field, prefix)
+
+	if len(keys) > 1 {
+		// do not pick one of the keys arbitrarily
+		collisions := make([]string, len(keys))
+		for k, key := range keys {
+			collisions[k] = fmt.Sprint(key.Interface())
+			consumed[collisions[k]] = true
+		}
+
+		return reflect.Value{}, false, fmt.Errorf("keys %s collide:(%s)", strings.Join(collisions, ", "), path)
+	}
+
+	if len(keys) == 0 {
+		// value not in map, fall back to the default value when there is one
+		def, ok := tagOpts.Get("default")
+		if !ok {
+			d.meta.Unset = append(d.meta.Unset, path)
+			return reflect.Value{}, false, nil
+		}
+
+		elem, err := parseString(def, fieldType)
+		if err != nil {
+			return reflect.Value{}, false, fmt.Errorf("%v:(%s)", err, name)
+		}
+
+		d.meta.Defaults = append(d.meta.Defaults, path)
+		return elem, true, nil
+	}
+
+	match := fmt.Sprint(keys[0].Interface())
+	key := joinPath(keyPath, match)
+	consumed[match] = true
+
+	// nor one of the fields a key matches
+	if other, ok := claimed.fields[match]; ok {
+		return reflect.Value{}, false, fmt.Errorf("key %s collides:(%s, %s)", match, other, path)
+	}
+	claimed.fields[match] = path
+
+	d.meta.Keys = append(d.meta.Keys, key)
+
+	value := input.MapIndex(keys[0]).Interface()
+
+	// values written with the "string" option are parsed back
+	if str, ok := value.(string); ok && tagOpts.Has("string") && isScalar(fieldType) {
+		elem, err := parseString(str, fieldType)
+		if err != nil {
+			return reflect.Value{}, false, fmt.Errorf("%v:(%s)", err, name)
+		}
+
+		return elem, true, nil
+	}
+
+	elem := reflect.New(fieldType).Elem()
+	if err := d.fromValue(value, elem, fieldType, key, path); err != nil {
+		return reflect.Value{}, false, fmt.Errorf("%v:(%s)", err, name)
+	}
+
+	return elem, true, nil
+}
+
+// parseString converts the string form of a value, such as the value of a
+// "default" tag option, into a value of the given type
+func parseString(def string, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem, err := parseString(def, t.Elem())
+		if err != nil {
+			return out, err
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		out.Set(ptr)
+	case reflect.String:
+		out.SetString(def)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return out, err
+		}
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// time.Duration is an int64 and is better expressed as "1m30s"
+		if t == reflect.TypeOf(time.Duration(0)) {
+			duration, err := time.ParseDuration(def)
+			if err != nil {
+				return out, err
+			}
+			out.SetInt(int64(duration))
+			break
+		}
+
+		i, err := strconv.ParseInt(def, 10, t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(def, 10, t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(def, t.Bits())
+		if err != nil {
+			return out, err
+		}
+		out.SetFloat(f)
+	default:
+		return out, fmt.Errorf("default value not supported for type %s", t.String())
+	}
+
+	return out, nil
+}
+
+// joinPath appends the given name to a dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,408 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFillStruct_Metadata(t *testing.T) {
+	type Address struct {
+		City string
+		Zip  string
+	}
+
+	type User struct {
+		Name    string
+		Age     int
+		Email   string
+		Address *Address
+	}
+
+	m := map[string]any{
+		"Name":  "gopher",
+		"Extra": true,
+		"Address": map[string]any{
+			"City":    "Paris",
+			"Country": "France",
+		},
+	}
+
+	user := &User{}
+	meta := FillStruct(m, user)
+
+	expected := DecodeMetadata{
+		Keys:   []string{"Name", "Address", "Address.City"},
+		Unused: []string{"Address.Country", "Extra"},
+		Unset:  []string{"Age", "Email", "Address.Zip"},
+	}
+
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("Metadata should be %+v, got: %+v", expected, meta)
+	}
+
+	if user.Name != "gopher" || user.Address == nil || user.Address.City != "Paris" {
+		t.Errorf("Struct has not been filled: %+v", user)
+	}
+}
+
+func TestFillStruct_MetadataNested(t *testing.T) {
+	type Item struct {
+		Price int
+	}
+
+	type A struct {
+		Name string
+	}
+
+	type B struct {
+		A     A
+		Items []Item
+	}
+
+	m := map[string]any{
+		"Name": "example",
+		"Items": []any{
+			map[string]any{"Price": 12},
+			map[string]any{"Cost": 13},
+		},
+	}
+
+	meta := FillStruct(m, &B{})
+
+	expected := DecodeMetadata{
+		Keys:   []string{"Name", "Items", "Items[0].Price"},
+		Unused: []string{"Items[1].Cost"},
+		Unset:  []string{"Items[1].Price"},
+	}
+
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("Metadata should be %+v, got: %+v", expected, meta)
+	}
+}
+
+func TestFillStruct_Default(t *testing.T) {
+	type Config struct {
+		Host    string        `structs:",default=localhost"`
+		Port    int           `structs:",default=8080"`
+		Debug   bool          `structs:",default=true"`
+		Ratio   *float64      `structs:",default=0.5"`
+		Timeout time.Duration `structs:",default=1m30s"`
+		Retries uint8
+	}
+
+	config := &Config{}
+	meta := FillStruct(map[string]any{"Port": 9090}, config)
+
+	ratio := 0.5
+	expected := &Config{
+		Host:    "localhost",
+		Port:    9090,
+		Debug:   true,
+		Ratio:   &ratio,
+		Timeout: 90 * time.Second,
+	}
+
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("Struct should be %+v, got: %+v", expected, config)
+	}
+
+	defaults := []string{"Host", "Debug", "Ratio", "Timeout"}
+	if !reflect.DeepEqual(meta.Defaults, defaults) {
+		t.Errorf("Defaults should be %v, got: %v", defaults, meta.Defaults)
+	}
+
+	if !reflect.DeepEqual(meta.Unset, []string{"Retries"}) {
+		t.Errorf("Unset should be [Retries], got: %v", meta.Unset)
+	}
+}
+
+func TestFillStruct_InvalidDefault(t *testing.T) {
+	type Config struct {
+		Port int `structs:",default=http"`
+	}
+
+	config := &Config{Port: 80}
+	_, err := New(config).FillStruct(map[string]any{})
+	if err == nil {
+		t.Fatal("An invalid default should return an error")
+	}
+
+	if config.Port != 80 {
+		t.Errorf("Struct should not be modified in case of error, got: %d", config.Port)
+	}
+}
+
+func TestFillStruct_NotAddressable(t *testing.T) {
+	animal := Animal{Name: "cougar"}
+
+	_, err := New(animal).FillStruct(map[string]any{"Name": "lion"})
+	if err != errNotAddressable {
+		t.Errorf("Filling a struct passed by value should return %v, got: %v", errNotAddressable, err)
+	}
+}
+
+func TestFillStruct_TagName(t *testing.T) {
+	type User struct {
+		UserID string `structs:"userId"`
+		Name   string
+	}
+
+	user := &User{}
+	meta := FillStruct(map[string]any{"userId": "42", "UserID": "24", "Name": "gopher"}, user)
+
+	if user.UserID != "42" || user.Name != "gopher" {
+		t.Errorf("Struct should be filled using the tag names, got: %+v", user)
+	}
+
+	if !reflect.DeepEqual(meta.Unused, []string{"UserID"}) {
+		t.Errorf("Unused should be [UserID], got: %v", meta.Unused)
+	}
+}
+
+func TestFillStruct_Alias(t *testing.T) {
+	type User struct {
+		UserID string `structs:"userId,alias=user_id|uid"`
+	}
+
+	for _, key := range []string{"userId", "user_id", "uid"} {
+		user := &User{}
+		meta := FillStruct(map[string]any{key: "42"}, user)
+
+		if user.UserID != "42" {
+			t.Errorf("Struct should be filled from the %q key, got: %+v", key, user)
+		}
+
+		if !reflect.DeepEqual(meta.Keys, []string{key}) {
+			t.Errorf("Keys should be [%s], got: %v", key, meta.Keys)
+		}
+	}
+}
+
+func TestFillStruct_CaseInsensitive(t *testing.T) {
+	type User struct {
+		UserID string
+		Name   string
+	}
+
+	user := &User{}
+	s := New(user, WithMatchPolicy(MatchCaseInsensitive))
+
+	if _, err := s.FillStruct(map[string]any{"userid": "42", "NAME": "gopher"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &User{UserID: "42", Name: "gopher"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Struct should be %+v, got: %+v", expected, user)
+	}
+}
+
+func TestFillStruct_KeyNormalizers(t *testing.T) {
+	type User struct {
+		UserID string
+	}
+
+	for _, key := range []string{"user_id", "userId", "UserID", "user-id"} {
+		user := &User{}
+		s := New(user, WithMatchPolicy(MatchCaseInsensitive), WithKeyNormalizers(RemoveSeparators))
+
+		if _, err := s.FillStruct(map[string]any{key: "42"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if user.UserID != "42" {
+			t.Errorf("Struct should be filled from the %q key, got: %+v", key, user)
+		}
+	}
+}
+
+func TestFillStruct_KeyCollision(t *testing.T) {
+	type User struct {
+		UserID string `structs:"userId,alias=uid"`
+		Name   string
+	}
+
+	user := &User{Name: "gopher"}
+	meta, err := New(user).FillStruct(map[string]any{"userId": "42", "uid": "24", "Name": "lion"})
+	if err == nil {
+		t.Fatal("Keys matching the same field should return an error")
+	}
+
+	expected := "keys userId, uid collide:(UserID)"
+	if err.Error() != expected {
+		t.Errorf("Error should be %q, got: %q", expected, err.Error())
+	}
+
+	if user.Name != "gopher" {
+		t.Errorf("Struct should not be modified in case of error, got: %+v", user)
+	}
+
+	if len(meta.Unused) != 0 {
+		t.Errorf("Colliding keys should not be reported as unused, got: %v", meta.Unused)
+	}
+
+	user = &User{}
+	s := New(user, WithMatchPolicy(MatchCaseInsensitive))
+	if _, err := s.FillStruct(map[string]any{"name": "gopher", "Name": "lion"}); err == nil {
+		t.Error("Keys matching the same field regardless of their case should return an error")
+	}
+}
+
+func TestFillStruct_FieldCollision(t *testing.T) {
+	type User struct {
+		UserID string
+		UserId string
+		Name   string
+	}
+
+	user := &User{Name: "gopher"}
+	s := New(user, WithMatchPolicy(MatchCaseInsensitive))
+
+	meta, err := s.FillStruct(map[string]any{"userid": "42", "name": "lion"})
+	if err == nil {
+		t.Fatal("A key matching several fields should return an error")
+	}
+
+	expected := "key userid collides:(UserID, UserId)"
+	if err.Error() != expected {
+		t.Errorf("Error should be %q, got: %q", expected, err.Error())
+	}
+
+	if *user != (User{Name: "gopher"}) {
+		t.Errorf("Struct should not be modified in case of error, got: %+v", user)
+	}
+
+	if !reflect.DeepEqual(meta.Keys, []string{"userid", "name"}) {
+		t.Errorf("Key should be reported once, got: %v", meta.Keys)
+	}
+}
+
+func TestFillStruct_FieldNameFallback(t *testing.T) {
+	type User struct {
+		Name  string `structs:"name"`
+		Email string `structs:"mail"`
+		Mail  string `structs:"email"`
+	}
+
+	user := &User{}
+	meta, err := New(user).FillStruct(map[string]any{"Name": "gopher", "Email": "gopher@go.dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &User{Name: "gopher", Email: "gopher@go.dev"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Struct should be %+v, got: %+v", expected, user)
+	}
+
+	// the Go field name does not take the key of another field
+	user = &User{}
+	if _, err := New(user, WithMatchPolicy(MatchCaseInsensitive)).FillStruct(map[string]any{"email": "gopher@go.dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = &User{Mail: "gopher@go.dev"}
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Struct should be %+v, got: %+v", expected, user)
+	}
+
+	if len(meta.Unset) != 1 || meta.Unset[0] != "Mail" {
+		t.Errorf("Unset should be [Mail], got: %v", meta.Unset)
+	}
+}
+
+func TestFillStruct_FallbackTagNames(t *testing.T) {
+	type User struct {
+		ID       int64   `json:"id,string"`
+		Name     string  `json:"name,omitempty"`
+		Ratio    float64 `json:"ratio,string"`
+		Password string  `json:"-"`
+	}
+
+	in := &User{ID: 42, Name: "gopher", Ratio: 0.25}
+	config := NewConfig(WithFallbackTagNames("json"))
+	m := config.Map(in)
+
+	m["Password"] = "secret"
+
+	out := &User{}
+	meta, err := config.FillStruct(m, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Struct should be %+v, got: %+v", in, out)
+	}
+
+	if !reflect.DeepEqual(meta.Unused, []string{"Password"}) {
+		t.Errorf("Ignored field should not be filled, got unused keys: %v", meta.Unused)
+	}
+
+	if _, err := config.FillStruct(map[string]any{"id": "gopher"}, out); err == nil {
+		t.Error("An invalid string value should return an error")
+	}
+}
+
+func TestFillStruct_NonStringMapKeys(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+
+	type A struct {
+		Ints   map[int]*Item
+		Uints  map[uint8]*Item
+		Floats map[float64]*Item
+		Bools  map[bool]*Item
+		Points map[Point]*Item
+	}
+
+	in := &A{
+		Ints:   map[int]*Item{-1: {Name: "int"}},
+		Uints:  map[uint8]*Item{7: {Name: "uint"}},
+		Floats: map[float64]*Item{1.5: {Name: "float"}},
+		Bools:  map[bool]*Item{true: {Name: "bool"}},
+		Points: map[Point]*Item{{X: 1, Y: 2}: {Name: "point"}},
+	}
+
+	for _, config := range []*Config{NewConfig(), NewConfig(WithMapKeyTypes())} {
+		out := &A{}
+		if _, err := config.FillStruct(config.Map(in), out); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("Struct should be %+v, got: %+v", in, out)
+		}
+	}
+
+	if _, err := New(&A{}).FillStruct(map[string]any{"Ints": map[string]any{"one": map[string]any{}}}); err == nil {
+		t.Error("An invalid int key should return an error")
+	}
+}
@@ -0,0 +1,332 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// EqualOption configures Equal
+type EqualOption func(*equalOptions)
+
+// equalOptions holds the options of Equal
+type equalOptions struct {
+	ignored          selection
+	tolerance        float64
+	empty            bool
+	times            bool
+	ignoreUnexported bool
+}
+
+// IgnoreFields leaves the fields at the given paths out of the comparison. For
+// more info about the paths, which support wildcards, refer to Struct types
+// Only() method.
+func IgnoreFields(paths ...string) EqualOption {
+	return func(o *equalOptions) {
+		o.ignored = append(o.ignored, newFieldMask(true, paths))
+	}
+}
+
+// EquateApprox compares the floats, and the parts of the complex numbers, as
+// equal when they differ by at most the given tolerance
+func EquateApprox(tolerance float64) EqualOption {
+	return func(o *equalOptions) {
+		o.tolerance = tolerance
+	}
+}
+
+// EquateEmpty compares the nil and empty slices and maps as equal
+func EquateEmpty() EqualOption {
+	return func(o *equalOptions) {
+		o.empty = true
+	}
+}
+
+// EquateTimes compares the time.Time values with their Equal method, so the
+// same instant in different locations is equal
+func EquateTimes() EqualOption {
+	return func(o *equalOptions) {
+		o.times = true
+	}
+}
+
+// IgnoreUnexported leaves the unexported fields out of the comparison, except
+// for the structs without exported fields, ie: time.Time
+func IgnoreUnexported() EqualOption {
+	return func(o *equalOptions) {
+		o.ignoreUnexported = true
+	}
+}
+
+// Equal reports whether a and b are deeply equal, like reflect.DeepEqual does
+// with the given options, along with an explanation of the first difference
+// found, ie: "$.Items[1].Price: 2 != 2.5". The fields ignored with "-" and the
+// ones with the "noequal" tag option are left out of the comparison. Example:
+//
+//	// Field is not compared
+//	UpdatedAt time.Time `structs:"updated_at,noequal"`
+func (c *Config) Equal(a, b any, opts ...EqualOption) (bool, string) {
+	o := &equalOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	e := &comparer{
+		options:  o,
+		tagNames: append([]string{c.tagName}, c.fallbackTagNames...),
+		naming:   c.naming,
+		visited:  make(map[[2]visit]bool),
+	}
+
+	diff := e.compare(reflect.ValueOf(a), reflect.ValueOf(b), "", o.ignored)
+	return diff == "", diff
+}
+
+// comparer compares two values
+type comparer struct {
+	options  *equalOptions
+	tagNames []string
+	naming   NamingStrategy
+	visited  map[[2]visit]bool
+}
+
+// compare returns the first difference between the given values found at the
+// given path, or an empty string when they are equal
+func (e *comparer) compare(a, b reflect.Value, path string, sel selection) string {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() == b.IsValid() {
+			return ""
+		}
+		return e.differ(path, a, b)
+	}
+
+	if a.Type() != b.Type() {
+		return fmt.Sprintf("%s: type %s != %s", refPath(path), a.Type(), b.Type())
+	}
+
+	if e.options.times && a.Type() == timeType && a.CanInterface() && b.CanInterface() {
+		if a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			return ""
+		}
+		return e.differ(path, a, b)
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() == b.IsNil() {
+				return ""
+			}
+			return e.differ(path, a, b)
+		}
+
+		if a.Kind() == reflect.Ptr {
+			if a.Pointer() == b.Pointer() {
+				return ""
+			}
+
+			// pointers compared already are assumed equal, which ends cycles
+			key := [2]visit{{ptr: a.Pointer(), typ: a.Type()}, {ptr: b.Pointer(), typ: b.Type()}}
+			if e.visited[key] {
+				return ""
+			}
+			e.visited[key] = true
+		}
+
+		return e.compare(a.Elem(), b.Elem(), path, sel)
+	case reflect.Slice, reflect.Map:
+		if e.options.empty && a.Len() == 0 && b.Len() == 0 {
+			return ""
+		}
+
+		if a.IsNil() != b.IsNil() {
+			return e.differ(path, a, b)
+		}
+
+		if a.Len() != b.Len() {
+			return fmt.Sprintf("%s: length %d != %d", refPath(path), a.Len(), b.Len())
+		}
+
+		if a.Kind() == reflect.Map {
+			return e.compareMaps(a, b, path, sel)
+		}
+
+		return e.compareElements(a, b, path, sel)
+	case reflect.Array:
+		return e.compareElements(a, b, path, sel)
+	case reflect.Struct:
+		return e.compareStructs(a, b, path, sel)
+	case reflect.Float32, reflect.Float64:
+		if e.floatEqual(a.Float(), b.Float()) {
+			return ""
+		}
+	case reflect.Complex64, reflect.Complex128:
+		if e.floatEqual(real(a.Complex()), real(b.Complex())) && e.floatEqual(imag(a.Complex()), imag(b.Complex())) {
+			return ""
+		}
+	case reflect.Bool:
+		if a.Bool() == b.Bool() {
+			return ""
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() == b.Int() {
+			return ""
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() == b.Uint() {
+			return ""
+		}
+	case reflect.String:
+		if a.String() == b.String() {
+			return ""
+		}
+	case reflect.Func:
+		// like reflect.DeepEqual, functions are only equal when both are nil
+		if a.IsNil() && b.IsNil() {
+			return ""
+		}
+	default:
+		// channels and unsafe pointers
+		if a.Pointer() == b.Pointer() {
+			return ""
+		}
+	}
+
+	return e.differ(path, a, b)
+}
+
+// compareElements compares the elements of the given slices or arrays
+func (e *comparer) compareElements(a, b reflect.Value, path string, sel selection) string {
+	for i := 0; i < a.Len(); i++ {
+		elemSel, ok := sel.element(fmt.Sprint(i))
+		if !ok {
+			continue
+		}
+
+		if diff := e.compare(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), elemSel); diff != "" {
+			return diff
+		}
+	}
+
+	return ""
+}
+
+// compareMaps compares the entries of the given maps of the same length, in
+// the order of their sorted keys
+func (e *comparer) compareMaps(a, b reflect.Value, path string, sel selection) string {
+	keys := a.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	for _, k := range keys {
+		key := fmt.Sprint(k)
+		elemSel, ok := sel.element(key)
+		if !ok {
+			continue
+		}
+
+		bv := b.MapIndex(k)
+		if !bv.IsValid() {
+			return fmt.Sprintf("%s: missing key %s", refPath(path), key)
+		}
+
+		if diff := e.compare(a.MapIndex(k), bv, joinPath(path, key), elemSel); diff != "" {
+			return diff
+		}
+	}
+
+	return ""
+}
+
+// compareStructs compares the fields of the given structs of the same type
+func (e *comparer) compareStructs(a, b reflect.Value, path string, sel selection) string {
+	t := a.Type()
+	ignoreUnexported := e.options.ignoreUnexported && hasExportedFields(t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && ignoreUnexported {
+			continue
+		}
+
+		if isIgnored(field, e.tagNames) {
+			continue
+		}
+
+		key, tagOpts := fieldKey(field, e.tagNames, e.naming)
+		if tagOpts.Has("noequal") {
+			continue
+		}
+
+		fieldSel, ok := sel.field(field.Name, key)
+		if !ok {
+			continue
+		}
+
+		if diff := e.compare(a.Field(i), b.Field(i), joinPath(path, field.Name), fieldSel); diff != "" {
+			return diff
+		}
+	}
+
+	return ""
+}
+
+// floatEqual compares the given floats within the tolerance
+func (e *comparer) floatEqual(a, b float64) bool {
+	return a == b || math.Abs(a-b) <= e.options.tolerance
+}
+
+// differ describes the difference between the given values
+func (e *comparer) differ(path string, a, b reflect.Value) string {
+	return fmt.Sprintf("%s: %s != %s", refPath(path), describe(a), describe(b))
+}
+
+// describe returns the representation of the given value in an explanation
+func describe(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return "nil"
+		}
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	default:
+		// pass
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,126 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	type Item struct {
+		SKU   string
+		Price float64
+	}
+
+	type Order struct {
+		ID        string
+		Items     []Item
+		UpdatedAt time.Time `structs:",noequal"`
+	}
+
+	a := &Order{ID: "o-1", Items: []Item{{SKU: "a", Price: 1}, {SKU: "b", Price: 2}}}
+	b := &Order{ID: "o-1", Items: []Item{{SKU: "a", Price: 1}, {SKU: "b", Price: 2}}, UpdatedAt: time.Now()}
+
+	if ok, diff := Equal(a, b); !ok {
+		t.Errorf("Equal should be true, got: %s", diff)
+	}
+
+	b.Items[1].Price = 2.5
+	ok, diff := Equal(a, b)
+	if ok {
+		t.Fatal("Equal should be false")
+	}
+
+	if expected := "$.Items[1].Price: 2 != 2.5"; diff != expected {
+		t.Errorf("Explanation should be %q, got: %q", expected, diff)
+	}
+
+	if ok, diff := Equal(a, b, IgnoreFields("Items[*].Price")); !ok {
+		t.Errorf("Equal should ignore the prices, got: %s", diff)
+	}
+
+	if ok, diff := Equal(a, b, EquateApprox(0.5)); !ok {
+		t.Errorf("Equal should compare the prices within the tolerance, got: %s", diff)
+	}
+}
+
+func TestEqual_Options(t *testing.T) {
+	type Order struct {
+		ID      string
+		Tags    []string
+		Created time.Time
+		cache   int
+	}
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := &Order{ID: "o-1", Created: created}
+	b := &Order{ID: "o-1", Tags: []string{}, Created: created.In(time.FixedZone("CET", 3600)), cache: 1}
+
+	ok, diff := Equal(a, b)
+	if ok {
+		t.Fatal("Equal should be false")
+	}
+
+	if expected := "$.Tags: nil != []"; diff != expected {
+		t.Errorf("Explanation should be %q, got: %q", expected, diff)
+	}
+
+	if ok, diff := Equal(a, b, EquateEmpty(), EquateTimes(), IgnoreUnexported()); !ok {
+		t.Errorf("Equal should be true, got: %s", diff)
+	}
+
+	if ok, diff := Equal(a, b, EquateEmpty(), EquateTimes()); ok || diff != "$.cache: 0 != 1" {
+		t.Errorf("Equal should compare the unexported fields, got: %s", diff)
+	}
+
+	type Item struct {
+		ID string
+	}
+
+	if ok, _ := Equal(a, &Item{ID: "o-1"}); ok {
+		t.Error("Values of different types should not be equal")
+	}
+}
+
+func TestEqual_Cycle(t *testing.T) {
+	type Node struct {
+		ID     string
+		Parent *Node
+	}
+
+	a, b := &Node{ID: "o-1"}, &Node{ID: "o-1"}
+	a.Parent, b.Parent = a, b
+
+	if ok, diff := Equal(a, b); !ok {
+		t.Errorf("Equal should be true, got: %s", diff)
+	}
+
+	b.Parent = &Node{ID: "o-2"}
+	if ok, diff := Equal(a, b); ok || diff != `$.Parent.ID: "o-1" != "o-2"` {
+		t.Errorf("Equal should report the parent ID, got: %s", diff)
+	}
+}
@@ -39,9 +39,9 @@ var (
 // Field represents a single struct field that encapsulates high level
 // functions around the field.
 type Field struct {
-	value      reflect.Value
-	field      reflect.StructField
-	defaultTag string
+	value    reflect.Value
+	field    reflect.StructField
+	tagNames []string
 }
 
 // Tag returns the value associated with key in the tag string. If there is no
@@ -50,6 +50,13 @@ func (f *Field) Tag(key string) string {
 	return f.field.Tag.Get(key)
 }
 
+// TagOptions returns the options of the field's tag, which is looked up in
+// the same tags as the struct it comes from
+func (f *Field) TagOptions() TagOptions {
+	_, opts := parseFieldTag(f.field, f.tagNames)
+	return opts
+}
+
 // Value returns the underlying value of the field. It panics if the field
 // is not exported.
 func (f *Field) Value() any {
@@ -66,13 +73,13 @@ func (f *Field) IsExported() bool {
 	return f.field.PkgPath == ""
 }
 
-// IsZero returns true if the given field is not initialized (has a zero value).
-// It panics if the field is not exported.
+// IsZero returns true if the given field is not initialized (has a zero value),
+// is an empty string, slice or map, or reports itself as zero with an IsZero
+// method. It panics if the field is not exported.
 func (f *Field) IsZero() bool {
-	zero := reflect.Zero(f.value.Type()).Interface()
-	current := f.Value()
+	_ = f.Value() // panics if the field is not exported
 
-	return reflect.DeepEqual(current, zero)
+	return isEmptyValue(f.value)
 }
 
 // Name returns the name of the given field
@@ -125,7 +132,7 @@ func (f *Field) Zero() error {
 //
 // It panics if field is not exported or if field's kind is not struct
 func (f *Field) Fields() []*Field {
-	return getFields(f.value, f.defaultTag)
+	return getFields(f.value, f.tagNames)
 }
 
 // Field returns the field from a nested struct. It panics if the nested struct
@@ -160,12 +167,13 @@ func (f *Field) FieldOk(name string) (*Field, bool) {
 	}
 
 	return &Field{
-		field: field,
-		value: v.FieldByName(name),
+		field:    field,
+		value:    v.FieldByName(name),
+		tagNames: f.tagNames,
 	}, true
 }
 
-func getFields(v reflect.Value, tagName string) []*Field {
+func getFields(v reflect.Value, tagNames []string) []*Field {
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
@@ -177,13 +185,14 @@ func getFields(v reflect.Value, tagName string) []*Field {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 
-		if tag := field.Tag.Get(tagName); tag == "-" {
+		if isIgnored(field, tagNames) {
 			continue
 		}
 
 		f := &Field{
-			field: field,
-			value: v.FieldByName(field.Name),
+			field:    field,
+			value:    v.FieldByName(field.Name),
+			tagNames: tagNames,
 		}
 
 		fields = append(fields, f)
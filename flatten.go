@@ -0,0 +1,131 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var errKeyCollision = errors.New("key collision")
+
+// CollisionPolicy defines how the fields written under the same key, usually
+// by a flattened struct, are converted
+type CollisionPolicy int
+
+const (
+	// CollisionOverwrite keeps the value of the last field written under the
+	// key, at the position of the first one
+	CollisionOverwrite CollisionPolicy = iota
+	// CollisionKeepFirst keeps the value of the first field written under the
+	// key
+	CollisionKeepFirst
+	// CollisionError reports an error naming the paths of both fields.
+	// MapErr, EntriesErr and OrderedErr return it while the other methods
+	// panic with it.
+	CollisionError
+)
+
+// flatten returns the entries the given entry of a field with the "flatten"
+// option is spliced into
+func flatten(entry Entry) []Entry {
+	switch {
+	case entry.Nested != nil:
+		return entry.Nested
+	case reflect.ValueOf(entry.Value).Kind() == reflect.Map:
+		// flattened maps are spliced in the order of their sorted keys
+		m := reflect.ValueOf(entry.Value)
+		keys := m.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return mapKey(keys[i]) < mapKey(keys[j])
+		})
+
+		entries := make([]Entry, 0, len(keys))
+		for _, key := range keys {
+			entries = append(entries, Entry{
+				Key:   mapKey(key),
+				Value: m.MapIndex(key).Interface(),
+				Field: entry.Field,
+				path:  joinPath(entry.path, mapKey(key)),
+			})
+		}
+		return entries
+	default:
+		return []Entry{entry}
+	}
+}
+
+// appendEntry appends the given entry to entries, unless its key is already
+// written in which case the collision policy applies. The keys map holds the
+// position of the keys in entries.
+func (s *Struct) appendEntry(entries []Entry, keys map[string]int, entry Entry) []Entry {
+	i, ok := keys[entry.Key]
+	if !ok {
+		keys[entry.Key] = len(entries)
+		return append(entries, entry)
+	}
+
+	switch s.config.collisionPolicy {
+	case CollisionKeepFirst:
+		// pass
+	case CollisionError:
+		panic(fmt.Errorf("%w: %s and %s write %q", errKeyCollision, refPath(entries[i].path), refPath(entry.path), entry.Key))
+	default:
+		entries[i] = entry
+	}
+
+	return entries
+}
+
+// isFlattened returns true when the given field is a struct, or a pointer to a
+// struct, with the "flatten" option which does not fill itself
+func isFlattened(field reflect.StructField, tagNames []string) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(fillerType) {
+		return false
+	}
+
+	_, tagOpts := parseFieldTag(field, tagNames)
+	return tagOpts.Has("flatten")
+}
+
+// flatPrefix returns the prefix of the keys of the given flattened field,
+// nested in a field whose keys have the given prefix
+func flatPrefix(field reflect.StructField, tagNames []string, prefix string) string {
+	_, tagOpts := parseFieldTag(field, tagNames)
+	if !tagOpts.Has("flatten") {
+		return prefix
+	}
+
+	p, _ := tagOpts.Get("prefix")
+	return prefix + p
+}
@@ -0,0 +1,182 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_FlattenPrefix(t *testing.T) {
+	type Database struct {
+		Host string `structs:"host"`
+		Port int    `structs:"port"`
+	}
+
+	type Config struct {
+		Name    string    `structs:"name"`
+		Primary Database  `structs:",flatten,prefix=db_"`
+		Replica *Database `structs:",flatten,prefix=replica_"`
+	}
+
+	c := &Config{
+		Name:    "app",
+		Primary: Database{Host: "primary", Port: 5432},
+		Replica: &Database{Host: "replica", Port: 5433},
+	}
+
+	expected := map[string]any{
+		"name":         "app",
+		"db_host":      "primary",
+		"db_port":      5432,
+		"replica_host": "replica",
+		"replica_port": 5433,
+	}
+
+	m := Map(c)
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	for _, opts := range [][]Option{nil, {WithEmbeddedPromotion()}} {
+		out := &Config{}
+		if _, err := NewConfig(opts...).FillStruct(m, out); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(out, c) {
+			t.Errorf("FillStruct should be %+v, got: %+v", c, out)
+		}
+	}
+}
+
+func TestMap_FlattenCollisionPolicy(t *testing.T) {
+	type Collision struct {
+		Name  string
+		Inner struct {
+			Name string
+		} `structs:",flatten"`
+	}
+
+	c := &Collision{Name: "outer"}
+	c.Inner.Name = "inner"
+
+	if m := New(c, WithCollisionPolicy(CollisionOverwrite)).Map(); m["Name"] != "inner" {
+		t.Errorf("CollisionOverwrite should keep the last value, got: %v", m["Name"])
+	}
+
+	if m := New(c, WithCollisionPolicy(CollisionKeepFirst)).Map(); m["Name"] != "outer" {
+		t.Errorf("CollisionKeepFirst should keep the first value, got: %v", m["Name"])
+	}
+
+	entries := New(c).Entries()
+	if len(entries) != 1 {
+		t.Errorf("Entries should hold a single entry, got: %+v", entries)
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, errKeyCollision) {
+			t.Fatalf("A collision should panic with %v, got: %v", errKeyCollision, err)
+		}
+
+		expected := `key collision: $.Name and $.Inner.Name write "Name"`
+		if err.Error() != expected {
+			t.Errorf("Error should be %q, got: %q", expected, err.Error())
+		}
+	}()
+
+	_ = New(c, WithCollisionPolicy(CollisionError)).Map()
+}
+
+func TestMapErr_FlattenCollision(t *testing.T) {
+	type Collision struct {
+		Name  string
+		Inner struct {
+			Name string
+		} `structs:",flatten"`
+	}
+
+	c := &Collision{Name: "outer"}
+	c.Inner.Name = "inner"
+
+	config := NewConfig(WithCollisionPolicy(CollisionError))
+
+	_, err := config.MapErr(c)
+	if !errors.Is(err, errKeyCollision) {
+		t.Fatalf("A collision should return %v, got: %v", errKeyCollision, err)
+	}
+
+	expected := `key collision: $.Name and $.Inner.Name write "Name"`
+	if err.Error() != expected {
+		t.Errorf("Error should be %q, got: %q", expected, err.Error())
+	}
+
+	if _, err := config.OrderedErr(c); !errors.Is(err, errKeyCollision) {
+		t.Errorf("A collision should return %v, got: %v", errKeyCollision, err)
+	}
+}
+
+func TestFillStruct_FlattenPointer(t *testing.T) {
+	type Database struct {
+		Host string `structs:"host"`
+		Port int    `structs:"port"`
+	}
+
+	type Config struct {
+		Ignored string    `structs:"-"`
+		Name    string    `structs:"name"`
+		Replica *Database `structs:",flatten,prefix=replica_"`
+	}
+
+	for _, opts := range [][]Option{nil, {WithEmbeddedPromotion()}} {
+		out := &Config{}
+		meta, err := NewConfig(opts...).FillStruct(Map(&Config{Name: "app"}), out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := (&Config{Name: "app"}); !reflect.DeepEqual(out, expected) {
+			t.Errorf("FillStruct should be %+v, got: %+v", expected, out)
+		}
+
+		if !reflect.DeepEqual(meta.Unset, []string{"Replica"}) {
+			t.Errorf("Unset should be [Replica], got: %v", meta.Unset)
+		}
+
+		replica := &Database{Host: "old"}
+		out = &Config{Replica: replica}
+		input := map[string]any{"name": "app", "replica_host": "new", "replica_port": "bad"}
+		if _, err := NewConfig(opts...).FillStruct(input, out); err == nil {
+			t.Fatal("FillStruct should return an error for an invalid port")
+		}
+
+		if out.Name != "" || out.Replica != replica || replica.Host != "old" {
+			t.Errorf("FillStruct should not modify the struct on error, got: %+v", out)
+		}
+	}
+}
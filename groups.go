@@ -0,0 +1,72 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ForGroups returns a copy of s whose Map, Entries, Ordered, Values, Names and
+// Fields only handle the fields belonging to at least one of the given groups,
+// which are listed by the "groups" tag option. The fields of the nested
+// structs are handled the same way. Example:
+//
+//	// Field is part of the "admin" view only
+//	Email string `structs:"email,groups=admin"`
+//
+//	// Field is part of both views
+//	Name string `structs:"name,groups=public|admin"`
+//
+// The fields without groups belong to the groups set with WithDefaultGroups,
+// or to every group when none is set.
+func (s *Struct) ForGroups(groups ...string) *Struct {
+	n := *s
+	n.groups = append([]string{}, groups...)
+	return &n
+}
+
+// inGroups returns true when the given field belongs to one of the groups of s
+func (s *Struct) inGroups(field reflect.StructField) bool {
+	_, tagOpts := parseFieldTag(field, s.tagNames())
+
+	fieldGroups := s.config.defaultGroups
+	if value, ok := tagOpts.Get("groups"); ok {
+		fieldGroups = strings.Split(value, "|")
+	} else if fieldGroups == nil {
+		return true
+	}
+
+	for _, group := range s.groups {
+		for _, fieldGroup := range fieldGroups {
+			if group == fieldGroup {
+				return true
+			}
+		}
+	}
+
+	return false
+}
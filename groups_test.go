@@ -0,0 +1,113 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStruct_ForGroups(t *testing.T) {
+	type Profile struct {
+		Bio   string `structs:"bio"`
+		Phone string `structs:"phone,groups=admin"`
+	}
+
+	type User struct {
+		ID      int     `structs:"id,groups=public|admin"`
+		Name    string  `structs:"name"`
+		Email   string  `structs:"email,groups=admin"`
+		Profile Profile `structs:"profile,groups=public|admin"`
+	}
+
+	u := &User{
+		ID:      1,
+		Name:    "Arsene",
+		Email:   "arsene@example.com",
+		Profile: Profile{Bio: "gopher", Phone: "0123"},
+	}
+
+	tests := []struct {
+		group  string
+		opts   []Option
+		m      map[string]any
+		names  []string
+		values []any
+	}{
+		{
+			group:  "public",
+			m:      map[string]any{"id": 1, "name": "Arsene", "profile": map[string]any{"bio": "gopher"}},
+			names:  []string{"ID", "Name", "Profile"},
+			values: []any{1, "Arsene", "gopher"},
+		},
+		{
+			group:  "admin",
+			m:      Map(u),
+			names:  []string{"ID", "Name", "Email", "Profile"},
+			values: Values(u),
+		},
+		{
+			group:  "public",
+			opts:   []Option{WithDefaultGroups("admin")},
+			m:      map[string]any{"id": 1, "profile": map[string]any{}},
+			names:  []string{"ID", "Profile"},
+			values: []any{1},
+		},
+	}
+
+	for _, test := range tests {
+		s := New(u, test.opts...).ForGroups(test.group)
+		if m := s.Map(); !reflect.DeepEqual(m, test.m) {
+			t.Errorf("Map for %s should be %+v, got: %+v", test.group, test.m, m)
+		}
+		if names := s.Names(); !reflect.DeepEqual(names, test.names) {
+			t.Errorf("Names for %s should be %v, got: %v", test.group, test.names, names)
+		}
+		if values := s.Values(); !reflect.DeepEqual(values, test.values) {
+			t.Errorf("Values for %s should be %v, got: %v", test.group, test.values, values)
+		}
+	}
+}
+
+func TestStruct_ForGroupsValues(t *testing.T) {
+	type Line struct {
+		Name string `structs:"name"`
+		Cost int    `structs:"cost,groups=admin"`
+	}
+
+	type Order struct {
+		Lines []Line `structs:"lines"`
+		Note  string `structs:"note"`
+	}
+
+	o := &Order{Lines: []Line{{Name: "a", Cost: 1}}, Note: "r"}
+
+	// slices and maps keep their representation with groups
+	values := New(o).ForGroups("public").Values()
+	if expected := Values(o); !reflect.DeepEqual(values, expected) {
+		t.Errorf("Values should be %+v, got: %+v", expected, values)
+	}
+}
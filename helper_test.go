@@ -25,7 +25,11 @@
 
 package structs
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
 
 // TEST DATA
 type Animal struct {
@@ -45,3 +49,56 @@ type Person struct {
 func (p *Person) String() string {
 	return fmt.Sprintf("%s(%d)", p.Name, p.Age)
 }
+
+type Date struct {
+	Day int
+}
+
+func (d Date) IsZero() bool {
+	return d.Day < 0
+}
+
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) StructsMap() (any, error) {
+	if m.Currency == "" {
+		return nil, errors.New("missing currency")
+	}
+
+	return map[string]any{
+		"amount": fmt.Sprintf("%d.%02d", m.Cents/100, m.Cents%100),
+		"ccy":    m.Currency,
+	}, nil
+}
+
+func (m *Money) StructsFill(v any) error {
+	fields, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("money expects a map, got %T", v)
+	}
+
+	amount, err := strconv.ParseFloat(fmt.Sprint(fields["amount"]), 64)
+	if err != nil {
+		return err
+	}
+
+	m.Cents = int64(amount*100 + 0.5)
+	m.Currency = fmt.Sprint(fields["ccy"])
+	return nil
+}
+
+type Point struct {
+	X, Y int
+}
+
+func (p Point) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d:%d", p.X, p.Y)), nil
+}
+
+func (p *Point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d:%d", &p.X, &p.Y)
+	return err
+}
@@ -0,0 +1,118 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errMapper = errors.New("cannot map")
+
+var (
+	mapperType = reflect.TypeOf((*Mapper)(nil)).Elem()
+	fillerType = reflect.TypeOf((*Filler)(nil)).Elem()
+)
+
+// Mapper is implemented by the types controlling their own representation in
+// the output of Map, Entries, Ordered and Values, at every level. The struct
+// given to Map, Entries and Ordered represents itself only with a map, whose
+// keys are then sorted. Example:
+//
+//	func (m Money) StructsMap() (any, error) {
+//		return map[string]any{"amount": m.Amount.String(), "ccy": m.Currency}, nil
+//	}
+//
+// Map panics when StructsMap returns an error, which MapErr returns instead.
+type Mapper interface {
+	StructsMap() (any, error)
+}
+
+// Filler is implemented by the types filling themselves from the value found
+// in the map given to FillStruct, usually the value returned by their
+// StructsMap method. The struct given to FillStruct fills itself from the
+// whole map. Example:
+//
+//	func (m *Money) StructsFill(v any) error {
+//		fields, ok := v.(map[string]any)
+//		...
+//	}
+type Filler interface {
+	StructsFill(any) error
+}
+
+// asMapper returns the Mapper implementation of the given value, which may be
+// implemented by a pointer to the value when it is addressable
+func asMapper(val reflect.Value) (Mapper, bool) {
+	if val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+
+	if !val.IsValid() {
+		return nil, false
+	}
+
+	if val.Kind() != reflect.Ptr && val.CanAddr() && val.Addr().Type().Implements(mapperType) {
+		return val.Addr().Interface().(Mapper), true
+	}
+
+	if !val.Type().Implements(mapperType) {
+		return nil, false
+	}
+
+	// a nil pointer cannot represent itself
+	if (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && val.IsNil() {
+		return nil, false
+	}
+
+	return val.Interface().(Mapper), true
+}
+
+// asFiller returns the Filler implementation of the given addressable value
+func asFiller(val reflect.Value) (Filler, bool) {
+	if val.Kind() == reflect.Ptr || !val.CanAddr() || !val.Addr().Type().Implements(fillerType) {
+		return nil, false
+	}
+
+	return val.Addr().Interface().(Filler), true
+}
+
+// mapped returns the representation of the value found at the given path when
+// it implements Mapper. It panics with errMapper when the representation fails.
+func mapped(val reflect.Value, path string) (any, bool) {
+	mapper, ok := asMapper(val)
+	if !ok {
+		return nil, false
+	}
+
+	value, err := mapper.StructsMap()
+	if err != nil {
+		panic(fmt.Errorf("%w: %w:(%s)", errMapper, err, path))
+	}
+
+	return value, true
+}
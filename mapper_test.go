@@ -0,0 +1,165 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_Mapper(t *testing.T) {
+	type Order struct {
+		ID     string
+		Total  Money
+		Refund *Money
+		Items  []Money
+		Fees   map[string]Money
+	}
+
+	order := &Order{
+		ID:     "o-1",
+		Total:  Money{Cents: 1230, Currency: "EUR"},
+		Refund: &Money{Cents: 5, Currency: "EUR"},
+		Items:  []Money{{Cents: 1000, Currency: "EUR"}},
+		Fees:   map[string]Money{"shipping": {Cents: 230, Currency: "EUR"}},
+	}
+
+	expected := map[string]any{
+		"ID":     "o-1",
+		"Total":  map[string]any{"amount": "12.30", "ccy": "EUR"},
+		"Refund": map[string]any{"amount": "0.05", "ccy": "EUR"},
+		"Items":  []any{map[string]any{"amount": "10.00", "ccy": "EUR"}},
+		"Fees":   map[string]any{"shipping": map[string]any{"amount": "2.30", "ccy": "EUR"}},
+	}
+
+	if m := Map(order); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	values := Values(order)
+	if total := values[1]; !reflect.DeepEqual(total, expected["Total"]) {
+		t.Errorf("Values should hold %+v, got: %+v", expected["Total"], total)
+	}
+}
+
+func TestMap_MapperError(t *testing.T) {
+	type Order struct {
+		Total Money
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok {
+			t.Fatal("Map should panic when StructsMap fails")
+		}
+
+		expected := "cannot map: missing currency:(Total)"
+		if err.Error() != expected {
+			t.Errorf("Error should be %q, got: %q", expected, err.Error())
+		}
+	}()
+
+	_ = Map(&Order{Total: Money{Cents: 1230}})
+}
+
+func TestMapErr_Mapper(t *testing.T) {
+	type Order struct {
+		Total Money
+	}
+
+	m, err := MapErr(&Order{Total: Money{}})
+	if !errors.Is(err, errMapper) {
+		t.Fatalf("MapErr should return %v, got: %v", errMapper, err)
+	}
+
+	if expected := "cannot map: missing currency:(Total)"; err.Error() != expected {
+		t.Errorf("Error should be %q, got: %q", expected, err.Error())
+	}
+
+	if m != nil {
+		t.Errorf("Map should be nil in case of error, got: %+v", m)
+	}
+}
+
+func TestFillStruct_Filler(t *testing.T) {
+	type Order struct {
+		ID     string
+		Total  Money
+		Refund *Money
+		Items  []Money
+		Fees   map[string]Money
+	}
+
+	expected := &Order{
+		ID:     "o-1",
+		Total:  Money{Cents: 1230, Currency: "EUR"},
+		Refund: &Money{Cents: 5, Currency: "EUR"},
+		Items:  []Money{{Cents: 1000, Currency: "EUR"}},
+		Fees:   map[string]Money{"shipping": {Cents: 230, Currency: "EUR"}},
+	}
+
+	out := &Order{}
+	if _, err := NewConfig().FillStruct(Map(expected), out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("FillStruct should be %+v, got: %+v", expected, out)
+	}
+
+	_, err := NewConfig().FillStruct(map[string]any{"Total": "12.30"}, &Order{})
+	if err == nil {
+		t.Error("FillStruct should return the error of StructsFill")
+	}
+}
+
+func TestMapper_Root(t *testing.T) {
+	money := &Money{Cents: 1230, Currency: "EUR"}
+	expected := map[string]any{"amount": "12.30", "ccy": "EUR"}
+
+	if m := Map(money); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	if values := Values(*money); !reflect.DeepEqual(values, []any{expected}) {
+		t.Errorf("Values should be %+v, got: %+v", []any{expected}, values)
+	}
+
+	out := &Money{}
+	meta, err := New(out).FillStruct(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *out != *money {
+		t.Errorf("FillStruct should be %+v, got: %+v", money, out)
+	}
+
+	if keys := []string{"amount", "ccy"}; !reflect.DeepEqual(meta.Keys, keys) || len(meta.Unused) != 0 {
+		t.Errorf("Keys should be %v without unused keys, got: %v and %v", keys, meta.Keys, meta.Unused)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy converts a struct field name into the key used by Map,
+// Names and FillStruct for fields without a tag name. Custom strategies can be
+// written as a plain func(string) string.
+type NamingStrategy func(name string) string
+
+// SnakeCase converts a field name to snake_case, ie: "UserID" becomes "user_id"
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// ScreamingSnakeCase converts a field name to SCREAMING_SNAKE_CASE, ie:
+// "UserID" becomes "USER_ID"
+func ScreamingSnakeCase(name string) string {
+	return strings.ToUpper(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase converts a field name to kebab-case, ie: "UserID" becomes "user-id"
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// CamelCase converts a field name to camelCase, ie: "UserID" becomes "userId"
+func CamelCase(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			runes := []rune(word)
+			runes[0] = unicode.ToUpper(runes[0])
+			word = string(runes)
+		}
+		words[i] = word
+	}
+
+	return strings.Join(words, "")
+}
+
+// fieldKey returns the key of the given field for the given tag names and its
+// tag options. The key is the tag name when set, otherwise the field name
+// converted by the naming strategy when there is one.
+func fieldKey(field reflect.StructField, tagNames []string, naming NamingStrategy) (string, TagOptions) {
+	name, tagOpts := parseFieldTag(field, tagNames)
+	if name != "" {
+		return name, tagOpts
+	}
+
+	if naming != nil {
+		return naming(field.Name), tagOpts
+	}
+
+	return field.Name, tagOpts
+}
+
+// splitWords splits a name into its words. A word starts after a separator
+// ("_", "-" or " "), on a lower to upper case transition or on the last upper
+// case letter of an acronym, ie: "HTTPServer" gives "HTTP" and "Server".
+func splitWords(name string) []string {
+	var (
+		words []string
+		word  []rune
+		runes = []rune(name)
+	)
+
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
@@ -0,0 +1,170 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		name      string
+		snake     string
+		screaming string
+		kebab     string
+		camel     string
+	}{
+		{"Name", "name", "NAME", "name", "name"},
+		{"UserID", "user_id", "USER_ID", "user-id", "userId"},
+		{"HTTPServer", "http_server", "HTTP_SERVER", "http-server", "httpServer"},
+		{"CreatedAt", "created_at", "CREATED_AT", "created-at", "createdAt"},
+		{"Address2Line", "address2_line", "ADDRESS2_LINE", "address2-line", "address2Line"},
+		{"ID", "id", "ID", "id", "id"},
+		{"already_snake", "already_snake", "ALREADY_SNAKE", "already-snake", "alreadySnake"},
+	}
+
+	for _, test := range tests {
+		if got := SnakeCase(test.name); got != test.snake {
+			t.Errorf("SnakeCase(%q) should be %q, got: %q", test.name, test.snake, got)
+		}
+		if got := ScreamingSnakeCase(test.name); got != test.screaming {
+			t.Errorf("ScreamingSnakeCase(%q) should be %q, got: %q", test.name, test.screaming, got)
+		}
+		if got := KebabCase(test.name); got != test.kebab {
+			t.Errorf("KebabCase(%q) should be %q, got: %q", test.name, test.kebab, got)
+		}
+		if got := CamelCase(test.name); got != test.camel {
+			t.Errorf("CamelCase(%q) should be %q, got: %q", test.name, test.camel, got)
+		}
+	}
+}
+
+func TestMap_Naming(t *testing.T) {
+	type Address struct {
+		StreetName string
+		ZipCode    string `structs:"zip"`
+	}
+
+	type User struct {
+		UserID    int
+		FirstName string `structs:"name"`
+		Address   Address
+		Location  Address `structs:",flatten"`
+	}
+
+	u := &User{
+		UserID:    42,
+		FirstName: "gopher",
+		Address:   Address{StreetName: "Main", ZipCode: "75001"},
+		Location:  Address{StreetName: "Broadway", ZipCode: "10001"},
+	}
+
+	s := New(u, WithNaming(SnakeCase))
+
+	expected := map[string]any{
+		"user_id": 42,
+		"name":    "gopher",
+		"address": map[string]any{
+			"street_name": "Main",
+			"zip":         "75001",
+		},
+		"street_name": "Broadway",
+		"zip":         "10001",
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+}
+
+func TestNames_Naming(t *testing.T) {
+	type Address struct {
+		StreetName string
+	}
+
+	type User struct {
+		UserID    int
+		FirstName string `structs:"name"`
+		Address   Address
+		Location  Address `structs:",flatten"`
+	}
+
+	s := New(&User{}, WithNaming(KebabCase))
+
+	names := s.Names()
+	sort.Strings(names)
+
+	expected := []string{"address", "location", "name", "user-id"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Names should be %v, got: %v", expected, names)
+	}
+}
+
+func TestFillStruct_Naming(t *testing.T) {
+	type Address struct {
+		StreetName string
+	}
+
+	type User struct {
+		UserID    int
+		FirstName string `structs:"name"`
+		Address   *Address
+	}
+
+	in := &User{UserID: 42, FirstName: "gopher", Address: &Address{StreetName: "Main"}}
+	config := NewConfig(WithNaming(CamelCase))
+	m := config.Map(in)
+
+	out := &User{}
+	meta, err := config.FillStruct(m, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Struct should be %+v, got: %+v", in, out)
+	}
+
+	expected := []string{"userId", "name", "address", "address.streetName"}
+	if !reflect.DeepEqual(meta.Keys, expected) {
+		t.Errorf("Keys should be %v, got: %v", expected, meta.Keys)
+	}
+}
+
+func TestMap_CustomNaming(t *testing.T) {
+	type A struct {
+		Name string
+	}
+
+	s := New(&A{Name: "gopher"}, WithNaming(func(name string) string { return "x_" + name }))
+
+	expected := map[string]any{"x_Name": "gopher"}
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Entry is a single key of a struct converted by Entries. Value is the same
+// value Map outputs for the key and Field is the struct field it comes from.
+// Nested holds the entries of a nested struct, in their declaration order.
+type Entry struct {
+	Key    string
+	Value  any
+	Field  *Field
+	Nested []Entry
+
+	// path of the field the entry comes from
+	path string
+}
+
+// OrderedMap is a map[string]any that remembers the insertion order of its
+// keys. It is marshaled to JSON with its keys in that order.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// NewOrderedMap creates an empty *OrderedMap
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		values: make(map[string]any),
+	}
+}
+
+// newOrderedMap creates an *OrderedMap from the given entries
+func newOrderedMap(entries []Entry) *OrderedMap {
+	m := NewOrderedMap()
+	for _, entry := range entries {
+		m.Set(entry.Key, entry.Value)
+	}
+	return m
+}
+
+// Set sets the value of the given key. A new key is appended after the
+// existing ones while an existing key keeps its position.
+func (m *OrderedMap) Set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of the given key. The boolean returns whether the key
+// was found.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete removes the given key
+func (m *OrderedMap) Delete(key string) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys in their insertion order
+func (m *OrderedMap) Keys() []string {
+	keys := make([]string, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+// Len returns the number of keys
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Map returns the keys and values as a map[string]any. Nested values are
+// returned as they are.
+func (m *OrderedMap) Map() map[string]any {
+	out := make(map[string]any, len(m.values))
+	for key, value := range m.values {
+		out[key] = value
+	}
+	return out
+}
+
+// MarshalJSON implements json.Marshaler. The keys are written in their
+// insertion order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,196 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 4)
+
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"b", "a", "c"}) {
+		t.Errorf("Keys should keep their insertion order, got: %v", keys)
+	}
+
+	if value, ok := m.Get("b"); !ok || value != 4 {
+		t.Errorf("Value of b should be 4, got: %v", value)
+	}
+
+	m.Delete("a")
+	m.Delete("unknown")
+
+	if m.Len() != 2 {
+		t.Errorf("Len should be 2, got: %d", m.Len())
+	}
+
+	if _, ok := m.Get("a"); ok {
+		t.Error("Deleted key should not be found")
+	}
+
+	expected := map[string]any{"b": 4, "c": 3}
+	if out := m.Map(); !reflect.DeepEqual(out, expected) {
+		t.Errorf("Map should be %v, got: %v", expected, out)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"b":4,"c":3}` {
+		t.Errorf("JSON should keep the keys order, got: %s", data)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	type Item struct {
+		Price int
+		Name  string
+	}
+
+	type Address struct {
+		Zip  string
+		City string
+	}
+
+	type Order struct {
+		Status   string
+		ID       int `structs:"id"`
+		Address  Address
+		Shipping Address `structs:",flatten"`
+		Items    []Item
+		Note     string `structs:",omitempty"`
+	}
+
+	o := &Order{
+		Status:   "paid",
+		ID:       42,
+		Address:  Address{Zip: "75001", City: "Paris"},
+		Shipping: Address{Zip: "69001", City: "Lyon"},
+		Items:    []Item{{Price: 12, Name: "book"}},
+	}
+
+	entries := Entries(o)
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+
+	expected := []string{"Status", "id", "Address", "Zip", "City", "Items"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Keys should be %v, got: %v", expected, keys)
+	}
+
+	address := entries[2]
+	if address.Field.Name() != "Address" {
+		t.Errorf("Field of Address entry should be Address, got: %s", address.Field.Name())
+	}
+
+	if len(address.Nested) != 2 || address.Nested[0].Key != "Zip" || address.Nested[1].Key != "City" {
+		t.Errorf("Nested entries should be in declaration order, got: %+v", address.Nested)
+	}
+
+	if !reflect.DeepEqual(address.Value, map[string]any{"Zip": "75001", "City": "Paris"}) {
+		t.Errorf("Value of Address entry should be its map, got: %+v", address.Value)
+	}
+
+	if entries[3].Field.Name() != "Zip" || entries[3].Value != "69001" {
+		t.Errorf("Flattened entries should come from the flattened struct, got: %+v", entries[3])
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	type Item struct {
+		Price int
+		Name  string
+	}
+
+	type Address struct {
+		Zip  string
+		City string
+	}
+
+	type Order struct {
+		Status   string
+		ID       int `structs:"id"`
+		Address  Address
+		Shipping Address `structs:",flatten"`
+		Items    []Item
+		Note     string `structs:",omitempty"`
+	}
+
+	o := &Order{
+		Status:   "paid",
+		ID:       42,
+		Address:  Address{Zip: "75001", City: "Paris"},
+		Shipping: Address{Zip: "69001", City: "Lyon"},
+		Items:    []Item{{Price: 12, Name: "book"}, {Price: 3, Name: "pen"}},
+	}
+
+	data, err := json.Marshal(Ordered(o))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"Status":"paid","id":42,"Address":{"Zip":"75001","City":"Paris"},"Zip":"69001","City":"Lyon",` +
+		`"Items":[{"Price":12,"Name":"book"},{"Price":3,"Name":"pen"}]}`
+	if string(data) != expected {
+		t.Errorf("JSON should be %s, got: %s", expected, data)
+	}
+
+	// Map must not be affected by an ordered conversion
+	s := New(o)
+	_ = s.Ordered()
+	if _, ok := s.Map()["Address"].(map[string]any); !ok {
+		t.Error("Map should still output nested structs as map[string]any")
+	}
+}
+
+func TestEntries_FlattenMap(t *testing.T) {
+	type A struct {
+		Name   string
+		Labels map[string]int `structs:",flatten"`
+	}
+
+	entries := Entries(A{Name: "gopher", Labels: map[string]int{"b": 2, "a": 1}})
+
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+
+	if !reflect.DeepEqual(keys, []string{"Name", "a", "b"}) {
+		t.Errorf("Flattened map keys should be sorted, got: %v", keys)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var errNilEmbedded = errors.New("cannot set a field of a nil embedded pointer to an unexported struct")
+
+// candidate is a field reachable from a struct through its embedded structs
+type candidate struct {
+	field  reflect.StructField
+	key    string
+	tagged bool
+}
+
+// promotedFields returns the fields of the struct type t the way
+// encoding/json sees them. The fields of the embedded structs, or pointer to
+// structs, without a tag name are promoted to t. When several fields share
+// the same key, the least nested one wins. When they are equally nested, the
+// only one with a tag name wins, otherwise all of them are left out. The
+// Index of the returned fields is their full index path from t and they are
+// in declaration order.
+func promotedFields(t reflect.Type, tagNames []string, naming NamingStrategy) []reflect.StructField {
+	type walk struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var (
+		candidates []candidate
+		next       = []walk{{typ: t}}
+		visited    = make(map[reflect.Type]bool)
+	)
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		// an embedded struct type already walked at a lower depth is
+		// shadowed by it, while the same type embedded twice at the same
+		// depth conflicts with itself
+		level := make(map[reflect.Type]bool)
+		for _, w := range current {
+			if visited[w.typ] {
+				continue
+			}
+			level[w.typ] = true
+
+			for i := 0; i < w.typ.NumField(); i++ {
+				sf := w.typ.Field(i)
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				if sf.Anonymous {
+					// unexported embedded non struct types are ignored
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				if isIgnored(sf, tagNames) {
+					continue
+				}
+
+				index := make([]int, len(w.index)+1)
+				copy(index, w.index)
+				index[len(w.index)] = i
+
+				name, _ := parseFieldTag(sf, tagNames)
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					// unexported embedded structs only promote their fields
+					if !sf.IsExported() {
+						continue
+					}
+
+					sf.Index = index
+					key, _ := fieldKey(sf, tagNames, naming)
+					candidates = append(candidates, candidate{field: sf, key: key, tagged: name != ""})
+					continue
+				}
+
+				next = append(next, walk{typ: ft, index: index})
+			}
+		}
+
+		for typ := range level {
+			visited[typ] = true
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		x, y := candidates[i], candidates[j]
+		if x.key != y.key {
+			return x.key < y.key
+		}
+		if len(x.field.Index) != len(y.field.Index) {
+			return len(x.field.Index) < len(y.field.Index)
+		}
+		return x.tagged && !y.tagged
+	})
+
+	var fields []reflect.StructField
+	for i := 0; i < len(candidates); {
+		j := i + 1
+		for j < len(candidates) && candidates[j].key == candidates[i].key {
+			j++
+		}
+
+		group := candidates[i:j]
+		i = j
+
+		// the dominant field is ambiguous
+		if len(group) > 1 &&
+			len(group[0].field.Index) == len(group[1].field.Index) &&
+			group[0].tagged == group[1].tagged {
+			continue
+		}
+
+		fields = append(fields, group[0].field)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x, y := fields[i].Index, fields[j].Index
+		for k := 0; k < len(x) && k < len(y); k++ {
+			if x[k] != y[k] {
+				return x[k] < y[k]
+			}
+		}
+		return len(x) < len(y)
+	})
+
+	return fields
+}
+
+// fieldByIndex returns the field of the struct v at the given index path,
+// allocating the nil embedded pointers on the way. With dryRun nothing is
+// allocated, it only checks that those pointers can be.
+func fieldByIndex(v reflect.Value, index []int, dryRun bool) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, errNilEmbedded
+				}
+
+				if dryRun {
+					return reflect.Value{}, nil
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, nil
+}
+
+// indexPath returns the dotted Go path of the field of t at the given index
+// path, ie: "Base.ID"
+func indexPath(t reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, x := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		field := t.Field(x)
+		names[i] = field.Name
+		t = field.Type
+	}
+
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,219 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type promoteBase struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PromoteAudit is exported so FillStruct can allocate it when embedded as a
+// nil pointer
+type PromoteAudit struct {
+	ID      int    `json:"audit_id"`
+	Comment string `json:"comment"`
+}
+
+type promoteName struct {
+	Name string
+}
+
+type promoteLabel struct {
+	Name string
+}
+
+type promoteTagged struct {
+	Name string `json:"Name"`
+}
+
+type promoteUser struct {
+	promoteBase
+	*PromoteAudit
+	promoteName
+	promoteLabel
+	Email string `json:"email"`
+}
+
+type promoteAccount struct {
+	promoteName
+	promoteTagged
+	Owner promoteBase `json:"owner"`
+}
+
+type promoteShadow struct {
+	promoteBase
+	ID string `json:"id"`
+}
+
+// jsonKeys returns the keys encoding/json outputs for the given value
+func jsonKeys(t *testing.T, v any) []string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func TestMap_EmbeddedPromotion(t *testing.T) {
+	config := NewConfig(WithTagName("json"), WithEmbeddedPromotion())
+
+	tests := []any{
+		&promoteUser{promoteBase: promoteBase{ID: 1}, PromoteAudit: &PromoteAudit{ID: 2, Comment: "ok"}, Email: "a@b.c"},
+		&promoteUser{promoteBase: promoteBase{ID: 1}},
+		&promoteAccount{promoteName: promoteName{Name: "a"}, promoteTagged: promoteTagged{Name: "b"}},
+		&promoteShadow{promoteBase: promoteBase{ID: 1}, ID: "outer"},
+	}
+
+	for _, test := range tests {
+		m := config.Map(test)
+
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		if expected := jsonKeys(t, test); !reflect.DeepEqual(keys, expected) {
+			t.Errorf("Keys of %T should be %v, got: %v", test, expected, keys)
+		}
+	}
+}
+
+func TestMap_EmbeddedPromotionValues(t *testing.T) {
+	config := NewConfig(WithTagName("json"), WithEmbeddedPromotion())
+
+	account := &promoteAccount{
+		promoteName:   promoteName{Name: "a"},
+		promoteTagged: promoteTagged{Name: "b"},
+		Owner:         promoteBase{ID: 3},
+	}
+
+	m := config.Map(account)
+	if m["Name"] != "b" {
+		t.Errorf("The tagged field should win, got: %v", m["Name"])
+	}
+
+	owner, ok := m["owner"].(map[string]any)
+	if !ok || owner["id"] != 3 {
+		t.Errorf("A named struct field should be nested, got: %+v", m["owner"])
+	}
+
+	shadow := config.Map(&promoteShadow{promoteBase: promoteBase{ID: 1}, ID: "outer"})
+	if shadow["id"] != "outer" {
+		t.Errorf("The less nested field should win, got: %v", shadow["id"])
+	}
+
+	user := &promoteUser{promoteBase: promoteBase{ID: 1}, Email: "a@b.c"}
+
+	names := config.Names(user)
+	expected := []string{"ID", "CreatedAt", "ID", "Comment", "Email"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Names should be %v, got: %v", expected, names)
+	}
+
+	// the fields of the nil embedded pointer are left out
+	values := config.Values(user)
+	if !reflect.DeepEqual(values, []any{1, "a@b.c"}) {
+		t.Errorf("Values should be the promoted fields values, got: %v", values)
+	}
+}
+
+func TestFillStruct_EmbeddedPromotion(t *testing.T) {
+	config := NewConfig(WithTagName("json"), WithEmbeddedPromotion())
+
+	created := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	in := &promoteUser{
+		promoteBase:  promoteBase{ID: 1, CreatedAt: created},
+		PromoteAudit: &PromoteAudit{ID: 2, Comment: "ok"},
+		Email:        "a@b.c",
+	}
+
+	out := &promoteUser{}
+	meta, err := config.FillStruct(config.Map(in), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Struct should be %+v, got: %+v", in, out)
+	}
+
+	if out.PromoteAudit == nil {
+		t.Fatal("The nil embedded pointer should be allocated")
+	}
+
+	if !reflect.DeepEqual(meta.Keys, []string{"id", "created_at", "audit_id", "comment", "email"}) {
+		t.Errorf("Keys should be the promoted keys, got: %v", meta.Keys)
+	}
+
+	if len(meta.Unset) != 0 {
+		t.Errorf("Every field should be set, got unset: %v", meta.Unset)
+	}
+
+	account := &promoteAccount{}
+	if _, err := config.FillStruct(map[string]any{"Name": "b", "owner": map[string]any{"id": 3}}, account); err != nil {
+		t.Fatal(err)
+	}
+
+	if account.promoteTagged.Name != "b" || account.promoteName.Name != "" || account.Owner.ID != 3 {
+		t.Errorf("Struct should be filled following the promotion rules, got: %+v", account)
+	}
+}
+
+func TestFillStruct_EmbeddedPromotionUnexportedPointer(t *testing.T) {
+	type audit struct {
+		Comment string
+	}
+
+	type A struct {
+		*audit
+	}
+
+	_, err := New(&A{}, WithEmbeddedPromotion()).FillStruct(map[string]any{"Comment": "ok"})
+	if err == nil {
+		t.Error("A nil embedded pointer to an unexported struct cannot be allocated")
+	}
+}
@@ -0,0 +1,126 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var errProtected = errors.New("field is protected")
+
+// ProtectionPolicy defines how FillStruct handles the keys of the map which
+// target a protected field, either with the "readonly" tag option or left out
+// with Only or Except. Example:
+//
+//	// Field is written by Map but never filled by FillStruct
+//	IsAdmin bool `structs:"is_admin,readonly"`
+//
+//	// Field is filled by FillStruct but never written by Map
+//	Password string `structs:"password,writeonly"`
+type ProtectionPolicy int
+
+const (
+	// ProtectionIgnore leaves the protected fields as they are. Their keys are
+	// reported as unused in the DecodeMetadata.
+	ProtectionIgnore ProtectionPolicy = iota
+	// ProtectionError makes FillStruct return an error naming the protected
+	// fields targeted by the map, including by their Go field name or, for
+	// the structs filled from the same map, by the keys of their fields
+	ProtectionError
+)
+
+// protect returns an error when the given protected field is targeted by a
+// key of the input map and the protection policy reports it
+func (d *decoder) protect(index map[string][]reflect.Value, claimed *claims, field reflect.StructField, path, prefix string) error {
+	if d.protection != ProtectionError || !d.targeted(index, claimed, field, prefix) {
+		return nil
+	}
+
+	return fmt.Errorf("%w:(%s)", errProtected, path)
+}
+
+// targeted returns true when a key of the input map would fill the given
+// field, or one of the fields of a struct filled from the same input map
+func (d *decoder) targeted(index map[string][]reflect.Value, claimed *claims, field reflect.StructField, prefix string) bool {
+	if !d.inlined(field) {
+		keys, _ := d.fieldKeys(index, claimed, field, prefix)
+		return len(keys) > 0
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	prefix = flatPrefix(field, d.tagNames, prefix)
+	fields := d.inlineFields(t)
+	nested := d.newClaims(fields, prefix)
+	for _, f := range fields {
+		if d.targeted(index, nested, f, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inlined returns true when the given field is a struct filled from the input
+// map of its parent rather than from a nested map
+func (d *decoder) inlined(field reflect.StructField) bool {
+	if d.promote {
+		return isFlattened(field, d.tagNames)
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	return field.Anonymous || (field.Type.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(fillerType)) || isFlattened(field, d.tagNames)
+}
+
+// inlineFields returns the fields of the given struct type filled by the
+// decoder
+func (d *decoder) inlineFields(t reflect.Type) []reflect.StructField {
+	if d.promote {
+		return promotedFields(t, d.tagNames, d.naming)
+	}
+
+	var fields []reflect.StructField
+	for _, field := range getFields(reflect.New(t).Elem(), d.tagNames) {
+		if field.IsEmbedded() || field.IsExported() {
+			fields = append(fields, field.field)
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,150 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMap_WriteOnly(t *testing.T) {
+	type Account struct {
+		ID       int    `structs:"id,readonly"`
+		Name     string `structs:"name"`
+		Password string `structs:"password,writeonly"`
+	}
+
+	a := &Account{ID: 1, Name: "Arsene", Password: "secret"}
+
+	expected := map[string]any{"id": 1, "name": "Arsene"}
+	if m := Map(a); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	if values := Values(a); len(values) != 2 {
+		t.Errorf("Values should not hold the password, got: %v", values)
+	}
+}
+
+func TestFillStruct_ReadOnly(t *testing.T) {
+	type Account struct {
+		ID       int    `structs:"id,readonly"`
+		Name     string `structs:"name"`
+		IsAdmin  bool   `structs:"is_admin,readonly,alias=admin"`
+		Password string `structs:"password,writeonly"`
+	}
+
+	input := map[string]any{"id": 2, "name": "Tochemey", "admin": true, "password": "secret"}
+
+	a := &Account{ID: 1}
+	meta, err := NewConfig().FillStruct(input, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Account{ID: 1, Name: "Tochemey", Password: "secret"}
+	if !reflect.DeepEqual(a, expected) {
+		t.Errorf("FillStruct should be %+v, got: %+v", expected, a)
+	}
+
+	if !reflect.DeepEqual(meta.Unused, []string{"admin", "id"}) {
+		t.Errorf("Unused should be [admin id], got: %v", meta.Unused)
+	}
+}
+
+func TestFillStruct_ProtectionError(t *testing.T) {
+	type Account struct {
+		ID    int    `structs:"id,readonly"`
+		Name  string `structs:"name"`
+		Email string `structs:"email"`
+	}
+
+	input := map[string]any{"id": 2, "name": "Tochemey", "email": "t@example.com"}
+
+	a := &Account{ID: 1}
+	_, err := New(a, WithProtectionPolicy(ProtectionError)).Only("name").FillStruct(input)
+	if !errors.Is(err, errProtected) {
+		t.Fatalf("FillStruct should return %v, got: %v", errProtected, err)
+	}
+
+	expected := "field is protected:(ID)\nfield is protected:(Email)"
+	if err.Error() != expected {
+		t.Errorf("Error should be %q, got: %q", expected, err.Error())
+	}
+
+	if a.Name != "" {
+		t.Error("FillStruct should not modify the struct on error")
+	}
+
+	if _, err := New(a, WithProtectionPolicy(ProtectionError)).FillStruct(map[string]any{"name": "Tochemey"}); err != nil {
+		t.Errorf("FillStruct should not return an error, got: %v", err)
+	}
+}
+
+func TestFillStruct_ProtectionFallback(t *testing.T) {
+	type Account struct {
+		Name    string `structs:"name"`
+		IsAdmin bool   `structs:"is_admin,readonly"`
+	}
+
+	_, err := New(&Account{}, WithProtectionPolicy(ProtectionError)).FillStruct(map[string]any{"IsAdmin": true})
+	if expected := "field is protected:(IsAdmin)"; err == nil || err.Error() != expected {
+		t.Errorf("Error should be %q, got: %v", expected, err)
+	}
+}
+
+func TestFillStruct_ProtectionNested(t *testing.T) {
+	type Wallet struct {
+		Balance int
+	}
+
+	type Account struct {
+		Name   string
+		Wallet Wallet `structs:",readonly"`
+	}
+
+	a := &Account{Wallet: Wallet{Balance: 1}}
+	_, err := New(a, WithProtectionPolicy(ProtectionError)).FillStruct(map[string]any{"Balance": 5})
+	if expected := "field is protected:(Wallet)"; err == nil || err.Error() != expected {
+		t.Errorf("Error should be %q, got: %v", expected, err)
+	}
+
+	type Profile struct {
+		Name   string
+		Wallet *Wallet `structs:",flatten,prefix=w_"`
+	}
+
+	p := &Profile{}
+	_, err = New(p, WithProtectionPolicy(ProtectionError)).Only("Name").FillStruct(map[string]any{"Name": "a", "w_Balance": 5})
+	if expected := "field is protected:(Wallet)"; err == nil || err.Error() != expected {
+		t.Errorf("Error should be %q, got: %v", expected, err)
+	}
+
+	if _, err := New(p, WithProtectionPolicy(ProtectionError)).Only("Name").FillStruct(map[string]any{"Name": "a"}); err != nil {
+		t.Errorf("FillStruct should not return an error, got: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Redactor replaces the value of a sensitive field in the output of Redacted.
+// Custom redactors can be written as a plain func(any) any.
+type Redactor func(value any) any
+
+// Mask is a Redactor replacing any value with "[REDACTED]"
+func Mask(any) any {
+	return "[REDACTED]"
+}
+
+// HashPrefix is a Redactor replacing a value with the first 8 hexadecimal
+// characters of the SHA-256 hash of its fmt representation, ie:
+// "sha256:2bb80d53". Equal values can be correlated without being exposed.
+func HashPrefix(value any) any {
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
+
+// Redacted is the same as Map but replaces the values of the sensitive fields
+// with the Redactor set with WithRedactor, Mask by default. A field is
+// sensitive when it has the "sensitive" tag option, or when its name or key
+// matches one of the patterns set with WithSensitivePatterns. Example:
+//
+//	// Field appears in map as "[REDACTED]"
+//	APIKey string `structs:"api_key,sensitive"`
+//
+// The structs held by nested structs, slices, maps and interfaces are
+// converted and redacted at any depth. The maps with string keys are converted
+// to map[string]any, where the values of the keys matching the patterns are
+// redacted as well.
+func (s *Struct) Redacted() map[string]any {
+	config := *s.config
+	config.deep = true
+
+	n := *s
+	n.config = &config
+	n.redacted = true
+	return n.Map()
+}
+
+// Redact returns a deep copy of the struct with the values of the sensitive
+// fields and map keys zeroed. A pointer to a struct is copied into a new pointer. For more
+// info about the sensitive fields refer to Redacted.
+func (s *Struct) Redact() any {
+	return s.redactCopy(reflect.ValueOf(s.raw), make(map[visit]reflect.Value)).Interface()
+}
+
+// isSensitive returns true when the given field found under the given key is
+// sensitive
+func (s *Struct) isSensitive(field reflect.StructField, key string) bool {
+	if _, tagOpts := parseFieldTag(field, s.tagNames()); tagOpts.Has("sensitive") {
+		return true
+	}
+
+	return s.isSensitiveKey(field.Name) || s.isSensitiveKey(key)
+}
+
+// isSensitiveKey returns true when the given field name or map key matches one
+// of the sensitive patterns
+func (s *Struct) isSensitiveKey(key string) bool {
+	for _, pattern := range s.config.sensitivePatterns {
+		if ok, _ := path.Match(pattern, strings.ToLower(key)); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redact returns the redacted form of the given value
+func (s *Struct) redact(val reflect.Value) any {
+	if s.config.redactor == nil {
+		return Mask(val.Interface())
+	}
+	return s.config.redactor(val.Interface())
+}
+
+// redactCopy returns a deep copy of the given value with the sensitive fields
+// of its structs zeroed. The copies map keeps the pointers shared, or
+// referencing their ancestors, as they are in the copy.
+func (s *Struct) redactCopy(val reflect.Value, copies map[visit]reflect.Value) reflect.Value {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return val
+		}
+
+		key := visit{ptr: val.Pointer(), typ: val.Type()}
+		if c, ok := copies[key]; ok {
+			return c
+		}
+
+		c := reflect.New(val.Type().Elem())
+		copies[key] = c
+		c.Elem().Set(s.redactCopy(val.Elem(), copies))
+		return c
+	case reflect.Interface:
+		if val.IsNil() {
+			return val
+		}
+
+		c := reflect.New(val.Type()).Elem()
+		c.Set(s.redactCopy(val.Elem(), copies))
+		return c
+	case reflect.Struct:
+		// the unexported fields are copied as they are
+		c := reflect.New(val.Type()).Elem()
+		c.Set(val)
+
+		t := val.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			key, _ := fieldKey(field, s.tagNames(), s.config.naming)
+			if s.isSensitive(field, key) {
+				c.Field(i).Set(reflect.Zero(field.Type))
+				continue
+			}
+
+			c.Field(i).Set(s.redactCopy(val.Field(i), copies))
+		}
+		return c
+	case reflect.Slice:
+		if val.IsNil() {
+			return val
+		}
+
+		c := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			c.Index(i).Set(s.redactCopy(val.Index(i), copies))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(val.Type()).Elem()
+		for i := 0; i < val.Len(); i++ {
+			c.Index(i).Set(s.redactCopy(val.Index(i), copies))
+		}
+		return c
+	case reflect.Map:
+		if val.IsNil() {
+			return val
+		}
+
+		c := reflect.MakeMapWithSize(val.Type(), val.Len())
+		for _, k := range val.MapKeys() {
+			if k.Kind() == reflect.String && s.isSensitiveKey(k.String()) {
+				c.SetMapIndex(k, reflect.Zero(val.Type().Elem()))
+				continue
+			}
+
+			c.SetMapIndex(k, s.redactCopy(val.MapIndex(k), copies))
+		}
+		return c
+	default:
+		return val
+	}
+}
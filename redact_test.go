@@ -0,0 +1,168 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	type Credentials struct {
+		User     string `structs:"user"`
+		Password string `structs:"password,sensitive"`
+	}
+
+	type Config struct {
+		Name        string                 `structs:"name"`
+		APIToken    string                 `structs:"api_token"`
+		Database    *Credentials           `structs:"database"`
+		Replicas    []Credentials          `structs:"replicas"`
+		Services    map[string]Credentials `structs:"services"`
+		Extra       []any                  `structs:"extra"`
+		Credentials Credentials            `structs:"credentials,omitnested"`
+	}
+
+	creds := Credentials{User: "admin", Password: "secret"}
+	c := &Config{
+		Name:        "app",
+		APIToken:    "token",
+		Database:    &creds,
+		Replicas:    []Credentials{creds},
+		Services:    map[string]Credentials{"cache": creds},
+		Extra:       []any{creds},
+		Credentials: creds,
+	}
+
+	redacted := map[string]any{"user": "admin", "password": "[REDACTED]"}
+
+	expected := map[string]any{
+		"name":        "app",
+		"api_token":   "[REDACTED]",
+		"database":    redacted,
+		"replicas":    []any{redacted},
+		"services":    map[string]any{"cache": redacted},
+		"extra":       []any{redacted},
+		"credentials": Credentials{User: "admin"},
+	}
+
+	config := NewConfig(WithSensitivePatterns("*token*"))
+	if m := config.Redacted(c); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Redacted should be %+v, got: %+v", expected, m)
+	}
+
+	hashed := New(c, WithRedactor(HashPrefix)).Redacted()
+	if password := hashed["database"].(map[string]any)["password"]; password != HashPrefix("secret") || password == "secret" {
+		t.Errorf("Password should be hashed, got: %v", password)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type Credentials struct {
+		User     string `structs:"user"`
+		Password string `structs:"password,sensitive"`
+	}
+
+	type Config struct {
+		Name        string                 `structs:"name"`
+		APIToken    string                 `structs:"api_token"`
+		Database    *Credentials           `structs:"database"`
+		Replicas    []Credentials          `structs:"replicas"`
+		Services    map[string]Credentials `structs:"services"`
+		Extra       []any                  `structs:"extra"`
+		unexported  string
+		Credentials Credentials `structs:"credentials,omitnested"`
+	}
+
+	creds := Credentials{User: "admin", Password: "secret"}
+	c := &Config{
+		Name:        "app",
+		APIToken:    "token",
+		Replicas:    []Credentials{creds},
+		Services:    map[string]Credentials{"cache": creds},
+		Extra:       []any{creds},
+		unexported:  "kept",
+		Credentials: creds,
+	}
+	c.Database = &c.Replicas[0]
+
+	out, ok := NewConfig(WithSensitivePatterns("*token*")).Redact(c).(*Config)
+	if !ok {
+		t.Fatal("Redact should return a *Config")
+	}
+
+	creds = Credentials{User: "admin"}
+	expected := &Config{
+		Name:        "app",
+		Database:    &creds,
+		Replicas:    []Credentials{creds},
+		Services:    map[string]Credentials{"cache": creds},
+		Extra:       []any{creds},
+		unexported:  "kept",
+		Credentials: creds,
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Redact should be %+v, got: %+v", expected, out)
+	}
+
+	if c.APIToken != "token" || c.Replicas[0].Password != "secret" {
+		t.Error("Redact should not modify the original struct")
+	}
+}
+
+func TestRedacted_MapKeys(t *testing.T) {
+	type Request struct {
+		Path    string            `structs:"path"`
+		Headers map[string]string `structs:"headers"`
+	}
+
+	r := &Request{Path: "/", Headers: map[string]string{"Accept": "*/*", "Password": "hunter2"}}
+	config := NewConfig(WithSensitivePatterns("password"))
+
+	expected := map[string]any{
+		"path":    "/",
+		"headers": map[string]any{"Accept": "*/*", "Password": "[REDACTED]"},
+	}
+
+	if m := config.Redacted(r); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Redacted should be %+v, got: %+v", expected, m)
+	}
+
+	headers := config.LogValue(r).Group()[1].Value.Group()
+	if password := headers[1]; password.Key != "Password" || password.Value.String() != "[REDACTED]" {
+		t.Errorf("LogValue should redact the password, got: %v", password)
+	}
+
+	out := config.Redact(r).(*Request)
+	if expected := map[string]string{"Accept": "*/*", "Password": ""}; !reflect.DeepEqual(out.Headers, expected) {
+		t.Errorf("Redact should be %+v, got: %+v", expected, out.Headers)
+	}
+
+	if r.Headers["Password"] != "hunter2" {
+		t.Error("Redact should not modify the original struct")
+	}
+}
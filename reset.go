@@ -0,0 +1,141 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var lockerType = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+
+// ResetOption configures Reset
+type ResetOption func(*resetOptions)
+
+// resetOptions holds the options of Reset
+type resetOptions struct {
+	defaults bool
+	pointers bool
+}
+
+// ResetToDefaults sets the fields having a "default" tag option to the
+// declared value instead of their zero value. Example:
+//
+//	Port int `structs:",default=8080"`
+func ResetToDefaults() ResetOption {
+	return func(o *resetOptions) {
+		o.defaults = true
+	}
+}
+
+// ResetThroughPointers resets the structs referenced by the non nil pointers
+// in place instead of setting the pointers to nil. The referenced structs may
+// be shared with other values, which see them reset as well.
+func ResetThroughPointers() ResetOption {
+	return func(o *resetOptions) {
+		o.pointers = true
+	}
+}
+
+// Reset zeroes every exported field of the struct, walking through the nested
+// structs, so that it can be reused, ie: from a sync.Pool. Pointers are set to
+// nil unless ResetThroughPointers is given. A tag value with the option of
+// "keep" preserves the field. Example:
+//
+//	// Field is not reset
+//	ID string `structs:"id,keep"`
+//
+// The unexported fields of the nested structs are left as is, and so are the
+// fields implementing sync.Locker, which may be held. Structs without exported
+// fields, such as time.Time, and fields with the option of "omitnested" are
+// reset as a whole. Fields ignored with "-" are left as is. It returns an
+// error if the struct is not addressable or when a default value cannot be
+// parsed, in which case its field is left as is.
+func (s *Struct) Reset(opts ...ResetOption) error {
+	if !s.value.CanSet() {
+		return errNotAddressable
+	}
+
+	var o resetOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return s.walk().reset(o)
+}
+
+// reset resets the fields of s
+func (s *Struct) reset(o resetOptions) (err error) {
+	for _, field := range s.structFields() {
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			continue
+		}
+
+		_, tagOpts := parseFieldTag(field, s.tagNames())
+		if tagOpts.Has("keep") || reflect.PointerTo(val.Type()).Implements(lockerType) {
+			continue
+		}
+
+		path := joinPath(s.path, field.Name)
+		elem := val
+		if val.Kind() == reflect.Ptr && o.pointers {
+			elem = val.Elem()
+		}
+
+		if elem.Kind() == reflect.Struct && !tagOpts.Has("omitnested") && hasExportedFields(elem.Type()) {
+			leave, _, ok := s.enter(elem.Addr().Interface(), path)
+			if !ok {
+				// the ancestor is being reset already
+				continue
+			}
+
+			if e := s.sub(elem.Addr().Interface(), path).reset(o); e != nil {
+				err = errors.Join(err, e)
+			}
+			leave()
+			continue
+		}
+
+		if def, ok := tagOpts.Get("default"); ok && o.defaults {
+			value, e := parseString(def, val.Type())
+			if e != nil {
+				err = errors.Join(err, fmt.Errorf("%v:(%s)", e, path))
+				continue
+			}
+
+			val.Set(value)
+			continue
+		}
+
+		val.Set(reflect.Zero(val.Type()))
+	}
+
+	return
+}
@@ -0,0 +1,185 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReset(t *testing.T) {
+	type Limits struct {
+		Max  int
+		Keep int `structs:",keep"`
+	}
+
+	type Request struct {
+		ID      string `structs:",keep"`
+		Name    string
+		Tags    []string
+		Created time.Time
+		Limits  Limits
+		Next    *Request
+		Ignored string `structs:"-"`
+	}
+
+	r := &Request{
+		ID:      "r-1",
+		Name:    "request",
+		Tags:    []string{"a"},
+		Created: time.Now(),
+		Limits:  Limits{Max: 3, Keep: 4},
+		Next:    &Request{Name: "next"},
+		Ignored: "ignored",
+	}
+
+	if err := Reset(r); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Request{ID: "r-1", Limits: Limits{Keep: 4}, Ignored: "ignored"}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("Reset should be %+v, got: %+v", expected, r)
+	}
+}
+
+func TestReset_Defaults(t *testing.T) {
+	type Limits struct {
+		Max  int `structs:",default=10"`
+		Keep int `structs:",keep"`
+	}
+
+	type Request struct {
+		ID     string `structs:",keep"`
+		Port   int    `structs:",default=8080"`
+		Limits Limits
+	}
+
+	r := &Request{ID: "r-1", Port: 80, Limits: Limits{Max: 3, Keep: 4}}
+	if err := Reset(r, ResetToDefaults()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Request{ID: "r-1", Port: 8080, Limits: Limits{Max: 10, Keep: 4}}
+	if !reflect.DeepEqual(r, expected) {
+		t.Errorf("Reset should be %+v, got: %+v", expected, r)
+	}
+}
+
+func TestReset_Errors(t *testing.T) {
+	type T struct {
+		Port int `structs:",default=http"`
+		Name string
+	}
+
+	if err := Reset(T{}); err != errNotAddressable {
+		t.Errorf("Resetting a struct passed by value should return %v, got: %v", errNotAddressable, err)
+	}
+
+	s := &T{Port: 80, Name: "name"}
+	if err := Reset(s, ResetToDefaults()); err == nil {
+		t.Error("Reset should return an error for an invalid default value")
+	}
+
+	if expected := (&T{Port: 80}); !reflect.DeepEqual(s, expected) {
+		t.Errorf("Reset should be %+v, got: %+v", expected, s)
+	}
+}
+
+func TestReset_UnexportedFields(t *testing.T) {
+	type Meta struct {
+		ID   string `structs:",keep"`
+		Name string
+		mu   sync.Mutex
+	}
+
+	type Job struct {
+		Meta Meta
+		Lock sync.Mutex
+	}
+
+	j := &Job{Meta: Meta{ID: "j-1", Name: "job"}}
+	j.Meta.mu.Lock()
+	j.Lock.Lock()
+
+	if err := Reset(j); err != nil {
+		t.Fatal(err)
+	}
+
+	if j.Meta.ID != "j-1" || j.Meta.Name != "" {
+		t.Errorf("Reset should keep the ID only, got: %q and %q", j.Meta.ID, j.Meta.Name)
+	}
+
+	if j.Meta.mu.TryLock() || j.Lock.TryLock() {
+		t.Error("Reset should leave the locks as they are")
+	}
+}
+
+func TestReset_Pointers(t *testing.T) {
+	type Lim struct {
+		ID  string `structs:",keep"`
+		Max int
+	}
+
+	type Node struct {
+		Lim    *Lim
+		Parent *Node `structs:",keep"`
+		Next   *Node
+	}
+
+	lim := &Lim{ID: "l-1", Max: 3}
+	parent := &Node{Lim: lim}
+	n := &Node{Lim: lim, Parent: parent, Next: parent}
+
+	if err := Reset(n); err != nil {
+		t.Fatal(err)
+	}
+
+	if n.Lim != nil || n.Next != nil || n.Parent != parent {
+		t.Errorf("Reset should set the pointers to nil but the kept one, got: %+v", n)
+	}
+
+	if parent.Lim != lim || *lim != (Lim{ID: "l-1", Max: 3}) {
+		t.Errorf("Reset should not modify the referenced structs, got: %+v", lim)
+	}
+
+	n = &Node{Lim: lim}
+	n.Next = n
+
+	if err := Reset(n, ResetThroughPointers()); err != nil {
+		t.Fatal(err)
+	}
+
+	if n.Lim != lim || *lim != (Lim{ID: "l-1"}) {
+		t.Errorf("Reset should walk through the pointer, got: %+v", n.Lim)
+	}
+
+	if n.Next != n {
+		t.Errorf("Reset should keep the pointer to an ancestor, got: %p", n.Next)
+	}
+}
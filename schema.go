@@ -0,0 +1,502 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JSONSchema returns the JSON Schema, draft 2020-12, of the struct type of v,
+// which is either a reflect.Type or a sample value. The properties follow the
+// keys Map emits for the fields, in their declaration order: tag names, "-",
+// "flatten" with its "prefix" and "string" are resolved the same way, and
+// "writeonly" fields are left out. A field is required unless Map may omit
+// it, with the "omitempty" or "omitzero" options, or it has the "required"
+// option. Nested named structs are defined under "$defs". The "enum",
+// "format", "description" and "default" tag options are added to the schema
+// of their field. Example:
+//
+//	Status string `structs:"status,enum=active|disabled,description='Account status'"`
+func (c *Config) JSONSchema(v any) ([]byte, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errNotStruct
+	}
+
+	g := &schemaGenerator{
+		config: c,
+		root:   t,
+		defs:   NewOrderedMap(),
+		names:  newTypeNames(),
+	}
+
+	schema := NewOrderedMap()
+	schema.Set("$schema", jsonSchemaDraft)
+	if err := g.object(schema, t); err != nil {
+		return nil, err
+	}
+
+	if g.defs.Len() > 0 {
+		schema.Set("$defs", g.defs)
+	}
+
+	return json.Marshal(schema)
+}
+
+// schemaGenerator generates the schema of a struct type
+type schemaGenerator struct {
+	config *Config
+	root   reflect.Type
+	defs   *OrderedMap
+	names  *typeNames
+}
+
+// object sets the properties of the struct type t in the given schema
+func (g *schemaGenerator) object(schema *OrderedMap, t reflect.Type) error {
+	properties := NewOrderedMap()
+
+	var required []string
+	if err := g.properties(properties, &required, t); err != nil {
+		return err
+	}
+
+	schema.Set("type", "object")
+	schema.Set("properties", properties)
+	if len(required) > 0 {
+		schema.Set("required", required)
+	}
+	schema.Set("additionalProperties", false)
+	return nil
+}
+
+// properties adds the properties of the fields of the struct type t to the
+// given properties
+func (g *schemaGenerator) properties(properties *OrderedMap, required *[]string, t reflect.Type) error {
+	for _, field := range typeFields(g.config, t) {
+		var (
+			schema any
+			err    error
+		)
+
+		if field.tagOpts.Has("string") && isScalar(field.Type) {
+			schema = typeSchema("string")
+		} else if schema, err = g.schema(field.Type); err != nil {
+			return fmt.Errorf("%v:(%s)", err, joinPath(t.Name(), field.path))
+		}
+
+		if schema, err = g.annotate(schema, field.Type, field.tagOpts); err != nil {
+			return fmt.Errorf("%v:(%s)", err, joinPath(t.Name(), field.path))
+		}
+
+		properties.Set(field.key, schema)
+		if !field.optional {
+			*required = append(*required, field.key)
+		}
+	}
+
+	return nil
+}
+
+// annotate adds the "enum", "format", "description" and "default" tag
+// options to the given schema of a field of type t
+func (g *schemaGenerator) annotate(schema any, t reflect.Type, tagOpts TagOptions) (any, error) {
+	annotations := NewOrderedMap()
+
+	if enum, ok := tagOpts.Get("enum"); ok {
+		var values []any
+		for _, value := range strings.Split(enum, "|") {
+			parsed, err := parseSchemaValue(value, t)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, parsed)
+		}
+		annotations.Set("enum", values)
+	}
+
+	if format, ok := tagOpts.Get("format"); ok {
+		annotations.Set("format", format)
+	}
+
+	if description, ok := tagOpts.Get("description"); ok {
+		annotations.Set("description", description)
+	}
+
+	if def, ok := tagOpts.Get("default"); ok {
+		parsed, err := parseSchemaValue(def, t)
+		if err != nil {
+			return nil, err
+		}
+		annotations.Set("default", parsed)
+	}
+
+	if annotations.Len() == 0 {
+		return schema, nil
+	}
+
+	// the annotations are added to a copy of the schema, which may be a
+	// shared reference
+	annotated := NewOrderedMap()
+	if m, ok := schema.(*OrderedMap); ok && !isRef(m) {
+		for _, key := range m.Keys() {
+			value, _ := m.Get(key)
+			annotated.Set(key, value)
+		}
+	} else {
+		annotated.Set("allOf", []any{schema})
+	}
+
+	for _, key := range annotations.Keys() {
+		value, _ := annotations.Get(key)
+		annotated.Set(key, value)
+	}
+	return annotated, nil
+}
+
+// schema returns the schema of the given type
+func (g *schemaGenerator) schema(t reflect.Type) (any, error) {
+	switch {
+	case t == timeType:
+		schema := typeSchema("string")
+		schema.Set("format", "date-time")
+		return schema, nil
+	case t == durationType:
+		return typeSchema("integer"), nil
+	case t.Implements(mapperType):
+		// the representation is only known at run time
+		return NewOrderedMap(), nil
+	case t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler) && t.Kind() != reflect.Ptr:
+		return typeSchema("string"), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return typeSchema("boolean"), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typeSchema("integer"), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema := typeSchema("integer")
+		schema.Set("minimum", 0)
+		return schema, nil
+	case reflect.Float32, reflect.Float64:
+		return typeSchema("number"), nil
+	case reflect.String:
+		return typeSchema("string"), nil
+	case reflect.Interface:
+		return NewOrderedMap(), nil
+	case reflect.Ptr:
+		elem, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			schema := typeSchema("string")
+			schema.Set("contentEncoding", "base64")
+			return nullable(schema), nil
+		}
+
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema := typeSchema("array")
+		schema.Set("items", items)
+		if t.Kind() == reflect.Array {
+			schema.Set("minItems", t.Len())
+			schema.Set("maxItems", t.Len())
+			return schema, nil
+		}
+		return nullable(schema), nil
+	case reflect.Map:
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema := typeSchema("object")
+		schema.Set("additionalProperties", values)
+		return nullable(schema), nil
+	case reflect.Struct:
+		return g.ref(t)
+	default:
+		return nil, fmt.Errorf("type %s is not supported", t)
+	}
+}
+
+// ref returns the schema of the struct type t, which is a reference to its
+// definition for the named types
+func (g *schemaGenerator) ref(t reflect.Type) (any, error) {
+	if !hasExportedFields(t) {
+		return typeSchema("object"), nil
+	}
+
+	if t.Name() == "" {
+		schema := NewOrderedMap()
+		if err := g.object(schema, t); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+
+	ref := NewOrderedMap()
+	if t == g.root {
+		ref.Set("$ref", "#")
+		return ref, nil
+	}
+
+	name, ok := g.names.lookup(t)
+	if !ok {
+		// the definition is registered before it is generated, for the
+		// recursive types
+		schema := NewOrderedMap()
+		g.defs.Set(name, schema)
+		if err := g.object(schema, t); err != nil {
+			return nil, err
+		}
+	}
+
+	ref.Set("$ref", "#/$defs/"+name)
+	return ref, nil
+}
+
+// typeNames gives unique names to the named struct types, suffixing the
+// names shared by types of different packages with a number
+type typeNames struct {
+	names map[reflect.Type]string
+	types map[string]reflect.Type
+}
+
+// newTypeNames creates a typeNames
+func newTypeNames() *typeNames {
+	return &typeNames{
+		names: make(map[reflect.Type]string),
+		types: make(map[string]reflect.Type),
+	}
+}
+
+// lookup returns the name of the struct type t. The boolean returns false when
+// t is given a name for the first time.
+func (n *typeNames) lookup(t reflect.Type) (string, bool) {
+	if name, ok := n.names[t]; ok {
+		return name, true
+	}
+
+	name := t.Name()
+	for i := 2; ; i++ {
+		if _, taken := n.types[name]; !taken {
+			break
+		}
+		name = fmt.Sprintf("%s%d", t.Name(), i)
+	}
+
+	n.names[t] = name
+	n.types[name] = t
+	return name, false
+}
+
+// typeSchema returns a schema of the given JSON type
+func typeSchema(typ string) *OrderedMap {
+	schema := NewOrderedMap()
+	schema.Set("type", typ)
+	return schema
+}
+
+// nullable returns the given schema accepting null, since a nil pointer,
+// slice or map is kept as nil by Map
+func nullable(schema any) any {
+	m, ok := schema.(*OrderedMap)
+	if !ok || isRef(m) || m.Len() == 0 {
+		return anyOfNull(schema)
+	}
+
+	typ, ok := m.Get("type")
+	if !ok {
+		return anyOfNull(schema)
+	}
+
+	if name, ok := typ.(string); ok {
+		n := NewOrderedMap()
+		for _, key := range m.Keys() {
+			value, _ := m.Get(key)
+			n.Set(key, value)
+		}
+		n.Set("type", []string{name, "null"})
+		return n
+	}
+
+	// the type accepts null already
+	return schema
+}
+
+// anyOfNull returns a schema accepting the given schema or null
+func anyOfNull(schema any) any {
+	if m, ok := schema.(*OrderedMap); ok && m.Len() == 0 {
+		// anything is accepted already
+		return schema
+	}
+
+	n := NewOrderedMap()
+	n.Set("anyOf", []any{schema, typeSchema("null")})
+	return n
+}
+
+// isRef returns true when the given schema is a reference
+func isRef(schema *OrderedMap) bool {
+	_, ok := schema.Get("$ref")
+	return ok
+}
+
+// typeField is a field of a struct type along with the key Map writes it
+// under
+type typeField struct {
+	reflect.StructField
+	key      string
+	path     string
+	tagOpts  TagOptions
+	optional bool
+}
+
+// typeFields returns the fields of the struct type t the way Map writes them:
+// "writeonly" fields are left out and the fields of the flattened structs are
+// spliced in. A field is optional when Map may leave it out, unless it has the
+// "required" option.
+func typeFields(c *Config, t reflect.Type) []typeField {
+	var (
+		fields []typeField
+		keys   = make(map[string]int)
+	)
+
+	var walk func(t reflect.Type, prefix, path string, optional bool)
+	walk = func(t reflect.Type, prefix, path string, optional bool) {
+		s := c.New(reflect.New(t).Interface())
+
+		for _, field := range s.structFields() {
+			key, tagOpts := s.fieldKey(field)
+			if tagOpts.Has("writeonly") {
+				continue
+			}
+
+			fieldPath := joinPath(path, indexPath(t, field.Index))
+			if elem, ok := flattenedType(field.Type, tagOpts); ok {
+				p, _ := tagOpts.Get("prefix")
+
+				// the fields of a flattened pointer are left out when nil
+				walk(elem, prefix+p, fieldPath, optional || field.Type.Kind() == reflect.Ptr)
+				continue
+			}
+
+			omitted := tagOpts.Has("omitempty") || tagOpts.Has("omitzero") || c.omitEmptyAll ||
+				optional || throughPointer(t, field.Index)
+
+			f := typeField{
+				StructField: field,
+				key:         prefix + key,
+				path:        fieldPath,
+				tagOpts:     tagOpts,
+				optional:    omitted && !tagOpts.Has("required"),
+			}
+
+			i, ok := keys[f.key]
+			switch {
+			case !ok:
+				keys[f.key] = len(fields)
+				fields = append(fields, f)
+			case c.collisionPolicy != CollisionKeepFirst:
+				fields[i] = f
+			}
+		}
+	}
+
+	walk(t, "", "", false)
+	return fields
+}
+
+// flattenedType returns the struct type whose fields are spliced in place of
+// a field of type t with the given tag options
+func flattenedType(t reflect.Type, tagOpts TagOptions) (reflect.Type, bool) {
+	if !tagOpts.Has("flatten") {
+		return nil, false
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	ok := t.Kind() == reflect.Struct && hasExportedFields(t) && !t.Implements(mapperType) && t != timeType
+	return t, ok
+}
+
+// throughPointer returns true when the field of t at the given index path is
+// promoted through an embedded pointer, which Map leaves out when nil
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Ptr {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchemaValue parses the given tag option value for a field of type t
+func parseSchemaValue(value string, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if !isScalar(t) || t == durationType {
+		return value, nil
+	}
+
+	parsed, err := parseString(value, t)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Interface(), nil
+}
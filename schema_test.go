@@ -0,0 +1,187 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaAddress struct {
+	Street string `structs:"street"`
+	City   string `structs:"city,omitempty"`
+}
+
+type schemaNode struct {
+	Name     string        `structs:"name"`
+	Children []*schemaNode `structs:"children,omitempty"`
+}
+
+type schemaUser struct {
+	ID        uint64            `structs:"id"`
+	Name      string            `structs:"name,description='Full name, as displayed'"`
+	Email     string            `structs:"email,omitempty,required,format=email"`
+	Status    string            `structs:"status,enum=active|disabled,default=active"`
+	Level     int               `structs:"level,enum=1|2|3"`
+	Score     float64           `structs:"score,omitempty"`
+	Tags      []string          `structs:"tags,omitempty"`
+	Labels    map[string]string `structs:"labels,omitempty"`
+	Address   schemaAddress     `structs:"address"`
+	Billing   *schemaAddress    `structs:"billing,omitempty"`
+	Tree      schemaNode        `structs:"tree"`
+	Created   time.Time         `structs:"created"`
+	Password  string            `structs:"password,writeonly"`
+	Count     int               `structs:"count,string"`
+	Secret    string            `structs:"-"`
+	Dimension struct {
+		Width int `structs:"width"`
+	} `structs:"dimension"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	got, err := JSONSchema(&schemaUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"id":{"type":"integer","minimum":0},` +
+		`"name":{"type":"string","description":"Full name, as displayed"},` +
+		`"email":{"type":"string","format":"email"},` +
+		`"status":{"type":"string","enum":["active","disabled"],"default":"active"},` +
+		`"level":{"type":"integer","enum":[1,2,3]},` +
+		`"score":{"type":"number"},` +
+		`"tags":{"type":["array","null"],"items":{"type":"string"}},` +
+		`"labels":{"type":["object","null"],"additionalProperties":{"type":"string"}},` +
+		`"address":{"$ref":"#/$defs/schemaAddress"},` +
+		`"billing":{"anyOf":[{"$ref":"#/$defs/schemaAddress"},{"type":"null"}]},` +
+		`"tree":{"$ref":"#/$defs/schemaNode"},` +
+		`"created":{"type":"string","format":"date-time"},` +
+		`"count":{"type":"string"},` +
+		`"dimension":{"type":"object","properties":{"width":{"type":"integer"}},"required":["width"],"additionalProperties":false}},` +
+		`"required":["id","name","email","status","level","address","tree","created","count","dimension"],` +
+		`"additionalProperties":false,` +
+		`"$defs":{` +
+		`"schemaAddress":{"type":"object","properties":{"street":{"type":"string"},"city":{"type":"string"}},"required":["street"],"additionalProperties":false},` +
+		`"schemaNode":{"type":"object","properties":{"name":{"type":"string"},"children":{"type":["array","null"],"items":{"anyOf":[{"$ref":"#/$defs/schemaNode"},{"type":"null"}]}}},"required":["name"],"additionalProperties":false}}}`
+
+	if string(got) != want {
+		t.Errorf("JSONSchema should be\n%s\ngot\n%s", want, got)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(got, &schema); err != nil {
+		t.Errorf("JSONSchema should be valid JSON: %v", err)
+	}
+}
+
+func TestJSONSchema_MatchesMap(t *testing.T) {
+	type Database struct {
+		Host string `structs:"host"`
+		Port int    `structs:"port"`
+	}
+
+	type Base struct {
+		ID string `structs:"id"`
+	}
+
+	type Server struct {
+		Base
+		Name     string
+		Database Database `structs:",flatten,prefix=db_"`
+	}
+
+	config := NewConfig(WithNaming(SnakeCase), WithEmbeddedPromotion())
+
+	got, err := config.JSONSchema(reflect.TypeOf(Server{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+		Required   []string       `json:"required"`
+	}
+	if err := json.Unmarshal(got, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	m := config.Map(Server{})
+	if len(m) != len(schema.Properties) {
+		t.Errorf("JSONSchema should have %d properties, got %v", len(m), schema.Properties)
+	}
+
+	for key := range m {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("JSONSchema should have the %q property", key)
+		}
+	}
+
+	want := []string{"id", "name", "db_host", "db_port"}
+	if !reflect.DeepEqual(schema.Required, want) {
+		t.Errorf("JSONSchema required should be %v, got %v", want, schema.Required)
+	}
+}
+
+func TestJSONSchema_Recursive(t *testing.T) {
+	got, err := JSONSchema(schemaNode{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"name":{"type":"string"},` +
+		`"children":{"type":["array","null"],"items":{"anyOf":[{"$ref":"#"},{"type":"null"}]}}},` +
+		`"required":["name"],"additionalProperties":false}`
+
+	if string(got) != want {
+		t.Errorf("JSONSchema should be\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestJSONSchema_Errors(t *testing.T) {
+	if _, err := JSONSchema("foo"); err == nil {
+		t.Error("JSONSchema should fail for a non struct")
+	}
+
+	type Invalid struct {
+		Level int `structs:"level,enum=low|high"`
+	}
+
+	if _, err := JSONSchema(Invalid{}); err == nil {
+		t.Error("JSONSchema should fail for an enum value not matching the field type")
+	}
+
+	type Unsupported struct {
+		Callback func()
+	}
+
+	if _, err := JSONSchema(Unsupported{}); err == nil {
+		t.Error("JSONSchema should fail for an unsupported field type")
+	}
+}
@@ -0,0 +1,268 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldMask is a set of field paths, split into their segments, which are
+// either kept or left out
+type fieldMask struct {
+	except   bool
+	patterns [][]string
+}
+
+// selection holds the field masks a struct is converted or filled with. A nil
+// selection selects every field.
+type selection []*fieldMask
+
+// Only returns a copy of s whose Map, Entries, Ordered, Values, Names, Fields
+// and FillStruct only handle the fields at the given paths, similar to a
+// protobuf FieldMask. A path is made of the names or keys of the fields,
+// separated by dots, and of the indexes of slice elements or the keys of map
+// entries, between brackets. The "*" and "[*]" wildcards match any field or
+// element. Example:
+//
+//	s.Only("Name", "Address.*", "Items[*].Price", "Labels[env]")
+//
+// The fields of a selected struct are selected as well. The elements of
+// slices and maps are selected by their fields when the path does not name
+// them. Only and Except can be chained.
+func (s *Struct) Only(paths ...string) *Struct {
+	return s.mask(false, paths)
+}
+
+// Except returns a copy of s which leaves the fields at the given paths out.
+// For more info about the paths refer to Only.
+func (s *Struct) Except(paths ...string) *Struct {
+	return s.mask(true, paths)
+}
+
+// mask returns a copy of s with a field mask of the given paths
+func (s *Struct) mask(except bool, paths []string) *Struct {
+	n := *s
+	n.selection = append(append(selection{}, s.selection...), newFieldMask(except, paths))
+	return &n
+}
+
+// newFieldMask creates a fieldMask of the given paths
+func newFieldMask(except bool, paths []string) *fieldMask {
+	mask := &fieldMask{except: except}
+	for _, path := range paths {
+		mask.patterns = append(mask.patterns, splitPath(path))
+	}
+	return mask
+}
+
+// splitPath splits the given path into its segments, ie: "Items[*].Price"
+// into "Items", "[*]" and "Price"
+func splitPath(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			i := strings.Index(part[1:], "[")
+			if i < 0 {
+				segments = append(segments, part)
+				break
+			}
+
+			segments = append(segments, part[:i+1])
+			part = part[i+1:]
+		}
+	}
+
+	return segments
+}
+
+// narrow returns the selection of the children of a field or an element. The
+// boolean returns false when it is left out.
+func (m *fieldMask) narrow(matched, whole bool, patterns [][]string) (*fieldMask, bool) {
+	if m.except {
+		if whole {
+			return nil, false
+		}
+
+		if len(patterns) == 0 {
+			return nil, true
+		}
+	} else {
+		if !matched {
+			return nil, false
+		}
+
+		if whole {
+			return nil, true
+		}
+	}
+
+	return &fieldMask{except: m.except, patterns: patterns}, true
+}
+
+// field returns the selection of the field with the given name and key
+func (m *fieldMask) field(name, key string) (*fieldMask, bool) {
+	var (
+		matched, whole bool
+		patterns       [][]string
+	)
+
+	for _, pattern := range m.patterns {
+		if segment := pattern[0]; segment != "*" && segment != name && segment != key {
+			continue
+		}
+
+		matched = true
+		if len(pattern) == 1 {
+			whole = true
+			continue
+		}
+		patterns = append(patterns, pattern[1:])
+	}
+
+	return m.narrow(matched, whole, patterns)
+}
+
+// element returns the selection of the slice element or map entry with the
+// given index or key. The patterns which do not name an element are passed
+// on to the element.
+func (m *fieldMask) element(key string) (*fieldMask, bool) {
+	var (
+		matched, whole bool
+		patterns       [][]string
+	)
+
+	for _, pattern := range m.patterns {
+		segment := pattern[0]
+		if !strings.HasPrefix(segment, "[") {
+			matched = true
+			patterns = append(patterns, pattern)
+			continue
+		}
+
+		if segment != "[*]" && segment != "["+key+"]" {
+			continue
+		}
+
+		matched = true
+		if len(pattern) == 1 {
+			whole = true
+			continue
+		}
+		patterns = append(patterns, pattern[1:])
+	}
+
+	return m.narrow(matched, whole, patterns)
+}
+
+// field returns the selection of the field with the given name and key. The
+// boolean returns false when the field is left out.
+func (sel selection) field(name, key string) (selection, bool) {
+	return sel.each(func(m *fieldMask) (*fieldMask, bool) {
+		return m.field(name, key)
+	})
+}
+
+// element returns the selection of the slice element or map entry with the
+// given index or key. The boolean returns false when it is left out.
+func (sel selection) element(key string) (selection, bool) {
+	return sel.each(func(m *fieldMask) (*fieldMask, bool) {
+		return m.element(key)
+	})
+}
+
+// each narrows every mask of the selection
+func (sel selection) each(narrow func(*fieldMask) (*fieldMask, bool)) (selection, bool) {
+	var narrowed selection
+	for _, m := range sel {
+		n, ok := narrow(m)
+		if !ok {
+			return nil, false
+		}
+
+		if n != nil {
+			narrowed = append(narrowed, n)
+		}
+	}
+
+	return narrowed, true
+}
+
+// selectField returns a copy of s converting the children of the given field
+// found under the given key. The boolean returns false when the field is left
+// out, either by the selection or by the groups of s.
+func (s *Struct) selectField(field reflect.StructField, key string) (*Struct, bool) {
+	if s.groups != nil && !s.inGroups(field) {
+		return nil, false
+	}
+
+	if s.selection == nil {
+		return s, true
+	}
+
+	sel, ok := s.selection.field(field.Name, key)
+	if !ok {
+		return nil, false
+	}
+
+	n := *s
+	n.selection = sel
+	return &n, true
+}
+
+// selectElement returns a copy of s converting the slice element or map entry
+// with the given index or key. The boolean returns false when it is left out.
+func (s *Struct) selectElement(key string) (*Struct, bool) {
+	if s.selection == nil {
+		return s, true
+	}
+
+	sel, ok := s.selection.element(key)
+	if !ok {
+		return nil, false
+	}
+
+	n := *s
+	n.selection = sel
+	return &n, true
+}
+
+// selectFields returns the given fields selected by s
+func (s *Struct) selectFields(fields []*Field) []*Field {
+	if s.selection == nil && s.groups == nil {
+		return fields
+	}
+
+	var selected []*Field
+	for _, field := range fields {
+		key, _ := s.fieldKey(field.field)
+		if _, ok := s.selectField(field.field, key); ok {
+			selected = append(selected, field)
+		}
+	}
+
+	return selected
+}
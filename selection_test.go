@@ -0,0 +1,210 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := map[string][]string{
+		"Name":           {"Name"},
+		"Address.*":      {"Address", "*"},
+		"Items[*].Price": {"Items", "[*]", "Price"},
+		"Matrix[0][1]":   {"Matrix", "[0]", "[1]"},
+	}
+
+	for path, expected := range tests {
+		if segments := splitPath(path); !reflect.DeepEqual(segments, expected) {
+			t.Errorf("Segments of %q should be %v, got: %v", path, expected, segments)
+		}
+	}
+}
+
+func TestStruct_Only(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type Item struct {
+		Name  string
+		Price int
+	}
+
+	type Order struct {
+		ID      string `structs:"id"`
+		Address Address
+		Items   []Item
+		Labels  map[string]Item
+	}
+
+	order := &Order{
+		ID:      "o-1",
+		Address: Address{Street: "Main", City: "Lomé"},
+		Items:   []Item{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+		Labels:  map[string]Item{"x": {Name: "x", Price: 3}, "y": {Name: "y", Price: 4}},
+	}
+
+	s := New(order).Only("id", "Address.City", "Items[*].Price", "Labels[x]")
+
+	expected := map[string]any{
+		"id":      "o-1",
+		"Address": map[string]any{"City": "Lomé"},
+		"Items":   []any{map[string]any{"Price": 1}, map[string]any{"Price": 2}},
+		"Labels":  map[string]any{"x": map[string]any{"Name": "x", "Price": 3}},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	if names := New(order).Only("ID", "Address.*").Names(); !reflect.DeepEqual(names, []string{"ID", "Address"}) {
+		t.Errorf("Names should be [ID Address], got: %v", names)
+	}
+
+	if fields := s.Fields(); len(fields) != 4 {
+		t.Errorf("Fields should hold 4 fields, got: %d", len(fields))
+	}
+
+	values := New(order).Only("Address.Street").Values()
+	if !reflect.DeepEqual(values, []any{"Main"}) {
+		t.Errorf("Values should be [Main], got: %v", values)
+	}
+}
+
+func TestStruct_Except(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type Item struct {
+		Name  string
+		Price int
+	}
+
+	type Order struct {
+		ID      string `structs:"id"`
+		Address Address
+		Items   []Item
+		Labels  map[string]Item
+	}
+
+	order := &Order{
+		ID:      "o-1",
+		Address: Address{Street: "Main", City: "Lomé"},
+		Items:   []Item{{Name: "a", Price: 1}, {Name: "b", Price: 2}},
+		Labels:  map[string]Item{"x": {Name: "x", Price: 3}},
+	}
+
+	s := New(order).Except("Address.Street", "Items[*].Name", "Items[1]", "Labels")
+
+	expected := map[string]any{
+		"id":      "o-1",
+		"Address": map[string]any{"City": "Lomé"},
+		"Items":   []any{map[string]any{"Price": 1}},
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	m := New(order).Only("Address.*", "Items").Except("Items[*].Name").Map()
+	expected = map[string]any{
+		"Address": map[string]any{"Street": "Main", "City": "Lomé"},
+		"Items":   []any{map[string]any{"Price": 1}, map[string]any{"Price": 2}},
+	}
+
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+}
+
+func TestStruct_OnlyFillStruct(t *testing.T) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type Item struct {
+		Name  string
+		Price int
+	}
+
+	type Order struct {
+		ID      string
+		Address *Address
+		Items   []Item
+	}
+
+	in := map[string]any{
+		"ID":      "o-1",
+		"Address": map[string]any{"Street": "Main", "City": "Lomé"},
+		"Items":   []any{map[string]any{"Name": "a", "Price": 1}, map[string]any{"Name": "b", "Price": 2}},
+	}
+
+	out := &Order{ID: "kept"}
+	meta, err := New(out).Only("Address.City", "Items[*].Price").FillStruct(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Order{
+		ID:      "kept",
+		Address: &Address{City: "Lomé"},
+		Items:   []Item{{Price: 1}, {Price: 2}},
+	}
+
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("FillStruct should be %+v, got: %+v", expected, out)
+	}
+
+	if len(meta.Unset) != 0 {
+		t.Errorf("Unset should be empty, got: %v", meta.Unset)
+	}
+}
+
+func TestStruct_OnlyValues(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price int
+	}
+
+	type Cart struct {
+		ID    string
+		Items []Item
+	}
+
+	c := &Cart{ID: "c-1", Items: []Item{{Name: "a", Price: 1}}}
+
+	// slices and maps keep their representation with a selection
+	values := New(c).Only("Items[*].Price").Values()
+	if expected := []any{c.Items}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("Values should be %+v, got: %+v", expected, values)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+var logValuerType = reflect.TypeOf(LogValuer{})
+
+// LogValuer implements slog.LogValuer for the struct it is created with. It is
+// meant to be embedded, so the struct is logged with LogValue. Example:
+//
+//	type Request struct {
+//		structs.LogValuer
+//		Path  string
+//		Token string `structs:",sensitive"`
+//	}
+//
+//	req := &Request{Path: "/", Token: "secret"}
+//	req.LogValuer = structs.NewLogValuer(req)
+//	slog.Info("request", "req", req) // => req.Path=/ req.Token=[REDACTED]
+//
+// The embedded LogValuer is left out of the logged attributes. Tag it with "-"
+// to leave it out of Map as well.
+type LogValuer struct {
+	value  any
+	config *Config
+}
+
+// NewLogValuer returns a LogValuer logging the given struct with the given
+// options
+func NewLogValuer(s any, opts ...Option) LogValuer {
+	return LogValuer{value: s, config: NewConfig(opts...)}
+}
+
+// LogValue implements slog.LogValuer
+func (l LogValuer) LogValue() slog.Value {
+	if l.value == nil {
+		return slog.GroupValue()
+	}
+	return l.config.New(l.value).LogValue()
+}
+
+// LogValue implements slog.LogValuer. The struct is logged as a group whose
+// attributes follow the fields declaration order, the same way Entries does:
+// tag names, "-", "omitempty" and the other options are respected, nested
+// structs become nested groups and the sensitive fields are redacted like
+// Redacted does. Times and durations are logged natively.
+func (s *Struct) LogValue() slog.Value {
+	config := *s.config
+	config.deep = true
+
+	n := *s
+	n.config = &config
+	n.ordered = true
+	n.redacted = true
+	return slog.GroupValue(logAttrs(n.Entries())...)
+}
+
+// logAttrs returns the attributes of the given entries
+func logAttrs(entries []Entry) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Field != nil && entry.Field.field.Type == logValuerType {
+			continue
+		}
+
+		if entry.Nested != nil {
+			attrs = append(attrs, slog.Attr{Key: entry.Key, Value: slog.GroupValue(logAttrs(entry.Nested)...)})
+			continue
+		}
+
+		attrs = append(attrs, slog.Attr{Key: entry.Key, Value: logValue(entry.Value)})
+	}
+
+	return attrs
+}
+
+// logValue returns the slog.Value of the given converted value
+func logValue(value any) slog.Value {
+	switch v := value.(type) {
+	case *OrderedMap:
+		attrs := make([]slog.Attr, 0, v.Len())
+		for _, key := range v.Keys() {
+			item, _ := v.Get(key)
+			attrs = append(attrs, slog.Attr{Key: key, Value: logValue(item)})
+		}
+		return slog.GroupValue(attrs...)
+	case map[string]any:
+		// maps are logged in the order of their sorted keys
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, key := range keys {
+			attrs = append(attrs, slog.Attr{Key: key, Value: logValue(v[key])})
+		}
+		return slog.GroupValue(attrs...)
+	case slog.LogValuer:
+		return v.LogValue()
+	}
+
+	// named types of basic kinds, ie: type Level int, are logged natively
+	// unless they describe themselves
+	val := reflect.ValueOf(value)
+	if _, ok := value.(interface{ String() string }); !ok && val.IsValid() {
+		switch val.Kind() {
+		case reflect.Bool:
+			return slog.BoolValue(val.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return slog.Int64Value(val.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return slog.Uint64Value(val.Uint())
+		case reflect.Float32, reflect.Float64:
+			return slog.Float64Value(val.Float())
+		case reflect.String:
+			return slog.StringValue(val.String())
+		default:
+			// pass
+		}
+	}
+
+	// times and durations are logged natively by slog.AnyValue
+	return slog.AnyValue(value)
+}
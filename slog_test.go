@@ -0,0 +1,117 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogValue(t *testing.T) {
+	type Database struct {
+		Host string `structs:"host"`
+	}
+
+	type Request struct {
+		Path     string        `structs:"path"`
+		Status   int           `structs:"status"`
+		Elapsed  time.Duration `structs:"elapsed"`
+		At       time.Time     `structs:"at"`
+		Database Database      `structs:"db"`
+		Note     string        `structs:"note,omitempty"`
+		Ignored  string        `structs:"-"`
+	}
+
+	v := LogValue(&Request{
+		Path:     "/orders",
+		Status:   200,
+		Elapsed:  1500 * time.Millisecond,
+		At:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Database: Database{Host: "localhost"},
+		Ignored:  "ignored",
+	})
+	if v.Kind() != slog.KindGroup {
+		t.Fatalf("LogValue should be a group, got: %v", v.Kind())
+	}
+
+	attrs := v.Group()
+	keys := make([]string, len(attrs))
+	for i, attr := range attrs {
+		keys[i] = attr.Key
+	}
+
+	if got := strings.Join(keys, " "); got != "path status elapsed at db" {
+		t.Errorf("Keys should be in the fields declaration order, got: %s", got)
+	}
+
+	if kind := attrs[2].Value.Kind(); kind != slog.KindDuration {
+		t.Errorf("Elapsed should be a duration, got: %v", kind)
+	}
+
+	if kind := attrs[3].Value.Kind(); kind != slog.KindTime {
+		t.Errorf("At should be a time, got: %v", kind)
+	}
+
+	if kind := attrs[4].Value.Kind(); kind != slog.KindGroup {
+		t.Errorf("Database should be a group, got: %v", kind)
+	}
+}
+
+func TestLogValuer(t *testing.T) {
+	type Database struct {
+		Host     string `structs:"host"`
+		Password string `structs:"password,sensitive"`
+	}
+
+	type Request struct {
+		LogValuer
+		Path     string   `structs:"path"`
+		Database Database `structs:"db"`
+	}
+
+	r := &Request{Path: "/orders", Database: Database{Host: "localhost", Password: "secret"}}
+	r.LogValuer = NewLogValuer(r)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("request", "req", r)
+
+	expected := "level=INFO msg=request req.path=/orders req.db.host=localhost req.db.password=[REDACTED]\n"
+	if buf.String() != expected {
+		t.Errorf("Log should be %q, got: %q", expected, buf.String())
+	}
+}
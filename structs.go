@@ -26,15 +26,21 @@
 package structs
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
+	"hash"
+	"log/slog"
 	"reflect"
+	"sort"
+	"strconv"
 )
 
 var (
 	// DefaultTagName is the default tag name for struct fields which provides
 	// a more granular to tweak certain structs. Lookup the necessary functions
-	// for more info.
+	// for more info. Changing it is not safe for concurrent use, use the
+	// WithTagName option or a Config instead.
 	DefaultTagName = "structs" // struct's field default tag name
 
 	errNotSlice        = errors.New("not a slice")
@@ -49,16 +55,25 @@ type Struct struct {
 	raw     any
 	value   reflect.Value
 	TagName string
+	config  *Config
+
+	ordered   bool
+	depth     int
+	path      string
+	visited   map[visit]string
+	selection selection
+	groups    []string
+	redacted  bool
 }
 
-// New returns a new *Struct with the struct s. It panics if the s's kind is
-// not struct.
-func New(s any) *Struct {
-	return &Struct{
-		raw:     s,
-		value:   structVal(s),
-		TagName: DefaultTagName,
-	}
+// New returns a new *Struct with the struct s configured with the given
+// options. Example:
+//
+//	s := structs.New(server, structs.WithTagName("json"), structs.WithNaming(structs.SnakeCase))
+//
+// It panics if the s's kind is not struct.
+func New(s any, opts ...Option) *Struct {
+	return NewConfig(opts...).New(s)
 }
 
 // Map converts the given struct to a map[string]any, where the keys
@@ -70,6 +85,9 @@ func New(s any) *Struct {
 //	// Field appears in map as key "myName".
 //	Name string `structs:"myName"`
 //
+// The key of the fields without a tag name can be converted with the WithNaming
+// option, ie: SnakeCase turns "UserID" into "user_id".
+//
 // A tag value with the content of "-" ignores that particular field. Example:
 //
 //	// Field is ignored by this package.
@@ -81,12 +99,28 @@ func New(s any) *Struct {
 //	// Map will panic if Animal does not implement String().
 //	Field *Animal `structs:"field,string"`
 //
+// Booleans and numbers that do not implement String() are formatted the same
+// way encoding/json does for its "string" option, so they can be parsed back
+// by FillStruct. Example:
+//
+//	// Field appears in map as "42" rather than 42.
+//	Field int `structs:",string"`
+//
 // A tag value with the option of "flatten" used in a struct field is to flatten its fields
 // in the output map. Example:
 //
 //	// The FieldStruct's fields will be flattened into the output map.
 //	FieldStruct time.Time `structs:",flatten"`
 //
+// The "prefix" option prepends a prefix to the keys of the flattened fields,
+// which FillStruct understands too. Example:
+//
+//	// The Database's fields appear in the output map as "db_host", ...
+//	Database Database `structs:",flatten,prefix=db_"`
+//
+// The fields written under the same key follow the CollisionPolicy set with
+// WithCollisionPolicy.
+//
 // A tag value with the option of "omitnested" stops iterating further if the type
 // is a struct. Example:
 //
@@ -105,6 +139,14 @@ func New(s any) *Struct {
 //	// the field is skipped if empty.
 //	Field string `structs:",omitempty"`
 //
+// Strings, slices and maps are empty when their length is zero, the other
+// values when they are zero. A tag value with the option of "omitzero" ignores
+// the field if its IsZero method, or its zero value when it has none, reports
+// it as zero. Example:
+//
+//	// Field is skipped if Field.IsZero() is true
+//	Field time.Time `structs:",omitzero"`
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields will be neglected.
 func (s *Struct) Map() map[string]any {
@@ -120,64 +162,130 @@ func (s *Struct) FillMap(out map[string]any) {
 		return
 	}
 
+	for _, entry := range s.Entries() {
+		out[entry.Key] = entry.Value
+	}
+}
+
+// Entries is the same as Map but returns the keys in the fields declaration
+// order. Each Entry holds the key and value Map would output along with the
+// Field it comes from. The Nested entries of a nested struct are in their
+// declaration order too, while the fields of a flattened struct take its
+// place in the returned slice.
+func (s *Struct) Entries() []Entry {
+	// a struct representing itself as a map gives its entries in the order
+	// of their sorted keys
+	if value, ok := mapped(s.value, s.path); ok && reflect.ValueOf(value).Kind() == reflect.Map {
+		return flatten(Entry{Value: value, path: s.path})
+	}
+
+	return s.walk().entries()
+}
+
+// entries returns the entries of s for the conversion in progress
+func (s *Struct) entries() []Entry {
 	fields := s.structFields()
 
+	var entries []Entry
+
+	// positions of the keys in entries
+	keys := make(map[string]int, len(fields))
+
 	for _, field := range fields {
-		name := field.Name
-		val := s.value.FieldByName(name)
-		isSubStruct := false
-		var finalVal any
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			continue
+		}
+		name, tagOpts := s.fieldKey(field)
+		path := joinPath(s.path, name)
 
-		tagName, tagOpts := parseTag(field.Tag.Get(s.TagName))
-		if tagName != "" {
-			name = tagName
+		fs, ok := s.selectField(field, name)
+		if !ok || tagOpts.Has("writeonly") {
+			continue
 		}
 
-		// if the value is a zero value and the field is marked as omitempty do
-		// not include
-		if tagOpts.Has("omitempty") {
-			zero := reflect.Zero(val.Type()).Interface()
-			current := val.Interface()
+		entry := Entry{
+			Key:  name,
+			path: path,
+			Field: &Field{
+				field:    field,
+				value:    val,
+				tagNames: s.tagNames(),
+			},
+		}
 
-			if reflect.DeepEqual(current, zero) {
-				continue
-			}
+		// if the value is empty and the field is marked as omitempty, or zero
+		// and the field is marked as omitzero, do not include
+		if (tagOpts.Has("omitempty") || s.config.omitEmptyAll) && isEmptyValue(val) {
+			continue
 		}
 
-		if !tagOpts.Has("omitnested") {
-			finalVal = s.nested(val)
+		if tagOpts.Has("omitzero") && isZeroValue(val) {
+			continue
+		}
 
-			v := reflect.ValueOf(val.Interface())
-			if v.Kind() == reflect.Ptr {
-				v = v.Elem()
-			}
+		if s.redacted && s.isSensitive(field, name) {
+			entry.Value = s.redact(val)
+			entries = s.appendEntry(entries, keys, entry)
+			continue
+		}
 
-			switch v.Kind() {
-			case reflect.Map, reflect.Struct:
-				isSubStruct = true
-			default:
-				// pass
+		if tagOpts.Has("string") {
+			if str, ok := stringValue(val); ok {
+				entry.Value = str
+				entries = s.appendEntry(entries, keys, entry)
 			}
-		} else {
-			finalVal = val.Interface()
+			continue
 		}
 
-		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
-			if ok {
-				out[name] = s.String()
+		if value, ok := mapped(val, path); ok {
+			entry.Value = value
+			entries = s.appendEntry(entries, keys, entry)
+			continue
+		}
+
+		if tagOpts.Has("omitnested") {
+			entry.Value = val.Interface()
+			if s.redacted {
+				// the value is kept as it is, without its secrets
+				entry.Value = s.redactCopy(val, make(map[visit]reflect.Value)).Interface()
 			}
+			entries = s.appendEntry(entries, keys, entry)
 			continue
 		}
 
-		if isSubStruct && (tagOpts.Has("flatten")) {
-			for k := range finalVal.(map[string]any) {
-				out[k] = finalVal.(map[string]any)[k]
+		if IsStruct(val.Interface()) {
+			entry.Value, entry.Nested, ok = fs.convert(val.Interface(), path)
+			if !ok {
+				continue
 			}
 		} else {
-			out[name] = finalVal
+			entry.Value = fs.nested(val, path)
+		}
+
+		if !tagOpts.Has("flatten") {
+			entries = s.appendEntry(entries, keys, entry)
+			continue
+		}
+
+		prefix, _ := tagOpts.Get("prefix")
+		for _, flat := range flatten(entry) {
+			flat.Key = prefix + flat.Key
+			entries = s.appendEntry(entries, keys, flat)
 		}
 	}
+
+	return entries
+}
+
+// Ordered is the same as Map but returns an *OrderedMap that keeps the keys in
+// the fields declaration order. Nested structs are converted to *OrderedMap as
+// well, including the ones held by slices and maps.
+func (s *Struct) Ordered() *OrderedMap {
+	n := *s
+	n.ordered = true
+	return newOrderedMap(n.Entries())
 }
 
 // Values converts the given s struct's field values to a []any.  A
@@ -200,41 +308,77 @@ func (s *Struct) FillMap(out map[string]any) {
 //	// Field is skipped if empty
 //	Field string `structs:",omitempty"`
 //
+// The "omitzero" option ignores the field when it is zero, like Map does. A
+// struct referencing one of its ancestors is handled by the cycle policy, like
+// Map does. A selection, see Only, or groups, see ForGroups, only leave out the
+// fields of the nested structs: slices and maps are returned as they are.
+//
 // Note that only exported fields of a struct can be accessed, non exported
 // fields  will be neglected.
 func (s *Struct) Values() []any {
+	if value, ok := mapped(s.value, s.path); ok {
+		return []any{value}
+	}
+
+	return s.walk().values()
+}
+
+// values returns the values of s for the conversion in progress
+func (s *Struct) values() []any {
 	fields := s.structFields()
 
 	var t []any
 
 	for _, field := range fields {
-		val := s.value.FieldByName(field.Name)
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			continue
+		}
 
-		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
+		name, tagOpts := s.fieldKey(field)
 
-		// if the value is a zero value and the field is marked as omitempty do
-		// not include
-		if tagOpts.Has("omitempty") {
-			zero := reflect.Zero(val.Type()).Interface()
-			current := val.Interface()
+		fs, ok := s.selectField(field, name)
+		if !ok || tagOpts.Has("writeonly") {
+			continue
+		}
 
-			if reflect.DeepEqual(current, zero) {
-				continue
-			}
+		// if the value is empty and the field is marked as omitempty, or zero
+		// and the field is marked as omitzero, do not include
+		if (tagOpts.Has("omitempty") || s.config.omitEmptyAll) && isEmptyValue(val) {
+			continue
+		}
+
+		if tagOpts.Has("omitzero") && isZeroValue(val) {
+			continue
 		}
 
 		if tagOpts.Has("string") {
-			s, ok := val.Interface().(fmt.Stringer)
-			if ok {
-				t = append(t, s.String())
+			if str, ok := stringValue(val); ok {
+				t = append(t, str)
 			}
 			continue
 		}
 
+		if value, ok := mapped(val, joinPath(s.path, field.Name)); ok {
+			t = append(t, value)
+			continue
+		}
+
 		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
+			path := joinPath(s.path, field.Name)
+			leave, ancestor, ok := s.enter(val.Interface(), path)
+			if !ok {
+				if value, _, ok := s.cycle(path, ancestor); ok {
+					t = append(t, value)
+				}
+				continue
+			}
+
 			// look out for embedded structs, and convert them to a
 			// []any to be added to the final values slice
-			t = append(t, Values(val.Interface())...)
+			t = append(t, fs.sub(val.Interface(), path).values()...)
+			leave()
 		} else {
 			t = append(t, val.Interface())
 		}
@@ -251,7 +395,7 @@ func (s *Struct) Values() []any {
 //
 // It panics if s's kind is not struct.
 func (s *Struct) Fields() []*Field {
-	return getFields(s.value, s.TagName)
+	return s.selectFields(getFields(s.value, s.tagNames()))
 }
 
 // Names returns a slice of field names. A struct tag with the content of "-"
@@ -260,14 +404,37 @@ func (s *Struct) Fields() []*Field {
 //	// Field is ignored by this package.
 //	Field bool `structs:"-"`
 //
+// When a naming strategy is set, Names returns the keys used by Map instead,
+// that is the tag name of the field or its converted name.
+//
 // It panics if s's kind is not struct.
 func (s *Struct) Names() []string {
-	fields := getFields(s.value, s.TagName)
+	if s.config.promoteEmbedded {
+		var names []string
+		for _, field := range s.structFields() {
+			key, _ := s.fieldKey(field)
+			if _, ok := s.selectField(field, key); !ok {
+				continue
+			}
+
+			name := field.Name
+			if s.config.naming != nil {
+				name = key
+			}
+			names = append(names, name)
+		}
+		return names
+	}
+
+	fields := s.selectFields(getFields(s.value, s.tagNames()))
 
 	names := make([]string, len(fields))
 
 	for i, field := range fields {
 		names[i] = field.Name()
+		if s.config.naming != nil {
+			names[i], _ = s.fieldKey(field.field)
+		}
 	}
 
 	return names
@@ -296,9 +463,9 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 	}
 
 	return &Field{
-		field:      field,
-		value:      s.value.FieldByName(name),
-		defaultTag: s.TagName,
+		field:    field,
+		value:    s.value.FieldByName(name),
+		tagNames: s.tagNames(),
 	}, true
 }
 
@@ -317,31 +484,46 @@ func (s *Struct) FieldOk(name string) (*Field, bool) {
 //	Field *http.Request `structs:",omitnested"`
 //
 // Note that only exported fields of a struct can be accessed, non exported
-// fields  will be neglected. It panics if s's kind is not struct.
+// fields  will be neglected. Empty strings, slices and maps are zero, and the
+// values with an IsZero method, such as time.Time, are checked with it. A
+// struct referencing one of its ancestors is not checked again. It panics if
+// s's kind is not struct.
 func (s *Struct) IsZero() bool {
+	return s.walk().isZero()
+}
+
+// isZero reports whether s is zero for the walk in progress
+func (s *Struct) isZero() bool {
 	fields := s.structFields()
 
 	for _, field := range fields {
-		val := s.value.FieldByName(field.Name)
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			continue
+		}
 
-		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
+		_, tagOpts := parseFieldTag(field, s.tagNames())
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := IsZero(val.Interface())
+		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") && !hasIsZero(val) {
+			path := joinPath(s.path, field.Name)
+			leave, _, ok := s.enter(val.Interface(), path)
 			if !ok {
+				// the ancestor is being checked already
+				continue
+			}
+
+			zero := s.sub(val.Interface(), path).isZero()
+			leave()
+			if !zero {
 				return false
 			}
 
 			continue
 		}
 
-		// zero value of the given field, such as "" for string, 0 for int
-		zero := reflect.Zero(val.Type()).Interface()
-
-		//  current value of the given field
-		current := val.Interface()
-
-		if !reflect.DeepEqual(current, zero) {
+		// the value is empty, such as "" for string, 0 for int or []int{}
+		if !isEmptyValue(val) {
 			return false
 		}
 	}
@@ -364,31 +546,44 @@ func (s *Struct) IsZero() bool {
 //	Field *http.Request `structs:",omitnested"`
 //
 // Note that only exported fields of a struct can be accessed, non exported
-// fields  will be neglected. It panics if s's kind is not struct.
+// fields  will be neglected. It follows the rules of IsZero. It panics if s's
+// kind is not struct.
 func (s *Struct) HasZero() bool {
+	return s.walk().hasZero()
+}
+
+// hasZero reports whether s has a zero field for the walk in progress
+func (s *Struct) hasZero() bool {
 	fields := s.structFields()
 
 	for _, field := range fields {
-		val := s.value.FieldByName(field.Name)
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			return true
+		}
 
-		_, tagOpts := parseTag(field.Tag.Get(s.TagName))
+		_, tagOpts := parseFieldTag(field, s.tagNames())
 
-		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") {
-			ok := HasZero(val.Interface())
-			if ok {
+		if IsStruct(val.Interface()) && !tagOpts.Has("omitnested") && !hasIsZero(val) {
+			path := joinPath(s.path, field.Name)
+			leave, _, ok := s.enter(val.Interface(), path)
+			if !ok {
+				// the ancestor is being checked already
+				continue
+			}
+
+			zero := s.sub(val.Interface(), path).hasZero()
+			leave()
+			if zero {
 				return true
 			}
 
 			continue
 		}
 
-		// zero value of the given field, such as "" for string, 0 for int
-		zero := reflect.Zero(val.Type()).Interface()
-
-		//  current value of the given field
-		current := val.Interface()
-
-		if reflect.DeepEqual(current, zero) {
+		// the value is empty, such as "" for string, 0 for int or []int{}
+		if isEmptyValue(val) {
 			return true
 		}
 	}
@@ -396,6 +591,63 @@ func (s *Struct) HasZero() bool {
 	return false
 }
 
+// FillStruct fills the struct with the provided map in place. The map keys are
+// matched against the field names, which can be changed in the struct field's
+// tag value just like with Map. A field with a tag name is still filled from
+// the key of its Go field name, unless another field uses that key. Embedded
+// and value structs are filled from the same map while pointer to structs are
+// filled from a nested map.
+//
+// A tag value with the option of "alias" lists, separated by "|", the other
+// keys matching the field. Example:
+//
+//	// Field is filled from the "userId", "user_id" or "uid" key.
+//	UserID string `structs:"userId,alias=user_id|uid"`
+//
+// The keys are compared according to the WithMatchPolicy option once rewritten
+// by the WithKeyNormalizers option. More than one key matching the same field,
+// or a key matching more than one field, is reported as an error rather than
+// resolved arbitrarily.
+//
+// A tag value with the option of "default" sets the field from the given value
+// when the map does not contain the field. Example:
+//
+//	// Port is set to 8080 when the map has no "Port" key.
+//	Port int `structs:",default=8080"`
+//
+// The returned DecodeMetadata reports the consumed keys, the unused keys, the
+// fields left unset and the fields set from their default. It returns an
+// error when the struct has not been created from a pointer.
+func (s *Struct) FillStruct(m map[string]any) (DecodeMetadata, error) {
+	if !s.value.CanSet() {
+		return DecodeMetadata{}, errNotAddressable
+	}
+
+	if filler, ok := asFiller(s.value); ok {
+		meta := DecodeMetadata{}
+		for key := range m {
+			meta.Keys = append(meta.Keys, key)
+		}
+		sort.Strings(meta.Keys)
+
+		return meta, filler.StructsFill(m)
+	}
+
+	d := &decoder{
+		tagNames:    s.tagNames(),
+		policy:      s.config.matchPolicy,
+		normalizers: s.config.normalizers,
+		naming:      s.config.naming,
+		promote:     s.config.promoteEmbedded,
+		selection:   s.selection,
+		protection:  s.config.protection,
+		meta:        new(DecodeMetadata),
+	}
+
+	err := d.toStruct(m, s.value, "", "")
+	return *d.meta, err
+}
+
 // Name returns the structs's type name within its package. For more info refer
 // to Name() function.
 func (s *Struct) Name() string {
@@ -413,6 +665,10 @@ func (s *Struct) Original() any {
 func (s *Struct) structFields() []reflect.StructField {
 	t := s.value.Type()
 
+	if s.config.promoteEmbedded {
+		return promotedFields(t, s.tagNames(), s.config.naming)
+	}
+
 	var f []reflect.StructField
 
 	for i := 0; i < t.NumField(); i++ {
@@ -423,7 +679,7 @@ func (s *Struct) structFields() []reflect.StructField {
 		}
 
 		// don't check if it's omitted
-		if tag := field.Tag.Get(s.TagName); tag == "-" {
+		if isIgnored(field, s.tagNames()) {
 			continue
 		}
 
@@ -433,9 +689,217 @@ func (s *Struct) structFields() []reflect.StructField {
 	return f
 }
 
-// nested retrieves recursively all types for the given value and returns the
-// nested value.
-func (s *Struct) nested(val reflect.Value) any {
+// fieldValue returns the value of the given field. The boolean returns false
+// when the field is promoted from a nil embedded pointer.
+func (s *Struct) fieldValue(field reflect.StructField) (reflect.Value, bool) {
+	v, err := s.value.FieldByIndexErr(field.Index)
+	return v, err == nil
+}
+
+// fieldKey returns the key of the given field and its tag options. The key is
+// the tag name when set, otherwise the field name converted by the naming
+// strategy.
+func (s *Struct) fieldKey(field reflect.StructField) (string, TagOptions) {
+	return fieldKey(field, s.tagNames(), s.config.naming)
+}
+
+// tagNames returns the tag names looked up, in order, for the fields of s
+func (s *Struct) tagNames() []string {
+	return append([]string{s.TagName}, s.config.fallbackTagNames...)
+}
+
+// sub returns a new *Struct for the nested struct v found at the given path
+// sharing the settings of s
+func (s *Struct) sub(v any, path string) *Struct {
+	n := s.config.New(v)
+	n.TagName = s.TagName
+	n.ordered = s.ordered
+	n.depth = s.depth + 1
+	n.path = path
+	n.visited = s.visited
+	n.selection = s.selection
+	n.groups = s.groups
+	n.redacted = s.redacted
+	return n
+}
+
+// convert converts the nested struct v found at the given path into a
+// map[string]any, or into an *OrderedMap for an ordered conversion, and
+// returns its entries as well. The boolean returns false when v should be left
+// out of the output.
+func (s *Struct) convert(v any, path string) (any, []Entry, bool) {
+	// keep the structs nested deeper than the maximum depth as they are
+	if s.config.maxDepth > 0 && s.depth >= s.config.maxDepth {
+		return v, nil, true
+	}
+
+	leave, ancestor, ok := s.enter(v, path)
+	if !ok {
+		return s.cycle(path, ancestor)
+	}
+	defer leave()
+
+	sub := s.sub(v, path)
+	entries := sub.entries()
+
+	// do not add the converted value if there are no exported fields, ie:
+	// time.Time, unless they have all been left out by a selection or groups
+	if len(entries) == 0 && (sub.selection == nil && sub.groups == nil || !hasExportedFields(sub.value.Type())) {
+		return v, nil, true
+	}
+
+	if s.ordered {
+		return newOrderedMap(entries), entries, true
+	}
+
+	m := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		m[entry.Key] = entry.Value
+	}
+
+	return m, entries, true
+}
+
+// convertMap converts the values of the map val found at the given path with
+// the given func, except for the structs which are converted to maps. The keys
+// which are not strings are formatted unless their type should be kept.
+func (s *Struct) convertMap(val reflect.Value, path string, nested func(*Struct, reflect.Value, string) any) any {
+	keepKeys := s.config.keepMapKeys && val.Type().Key().Kind() != reflect.String
+
+	m := make(map[string]any, val.Len())
+	kept := make(map[any]any, val.Len())
+	for _, k := range val.MapKeys() {
+		key := mapKey(k)
+		elem := val.MapIndex(k)
+
+		es, ok := s.selectElement(key)
+		if !ok {
+			continue
+		}
+
+		if s.redacted && k.Kind() == reflect.String && s.isSensitiveKey(key) {
+			m[key] = s.redact(elem)
+			continue
+		}
+
+		value, ok := mapped(elem, joinPath(path, key))
+		if ok {
+			// pass
+		} else if IsStruct(elem.Interface()) {
+			if value, _, ok = es.convert(elem.Interface(), joinPath(path, key)); !ok {
+				continue
+			}
+		} else {
+			value = nested(es, elem, joinPath(path, key))
+		}
+
+		if keepKeys {
+			kept[k.Interface()] = value
+			continue
+		}
+		m[key] = value
+	}
+
+	if keepKeys {
+		return kept
+	}
+	return m
+}
+
+// deep converts every struct held by the value val found at the given path,
+// at any depth, using the dynamic type of the values held by interfaces.
+func (s *Struct) deep(val reflect.Value, path string) any {
+	if value, ok := mapped(val, path); ok {
+		return value
+	}
+
+	for val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return val.Interface()
+		}
+		val = val.Elem()
+	}
+
+	if !val.IsValid() {
+		return nil
+	}
+
+	if IsStruct(val.Interface()) {
+		value, _, _ := s.convert(val.Interface(), path)
+		return value
+	}
+
+	switch val.Kind() {
+	case reflect.Map:
+		// the string keys of a redacted map may be sensitive
+		redactKeys := s.redacted && val.Type().Key().Kind() == reflect.String
+		if val.IsNil() || (!mayHoldStruct(val.Type().Elem(), nil) && !redactKeys) {
+			return val.Interface()
+		}
+		return s.convertMap(val, path, (*Struct).deep)
+	case reflect.Slice, reflect.Array:
+		if (val.Kind() == reflect.Slice && val.IsNil()) || !mayHoldStruct(val.Type().Elem(), nil) {
+			return val.Interface()
+		}
+
+		slices := make([]any, 0, val.Len())
+		for x := 0; x < val.Len(); x++ {
+			es, ok := s.selectElement(strconv.Itoa(x))
+			if !ok {
+				continue
+			}
+			slices = append(slices, es.deep(val.Index(x), fmt.Sprintf("%s[%d]", path, x)))
+		}
+		return slices
+	default:
+		return val.Interface()
+	}
+}
+
+// mayHoldStruct returns true when a value of the given type may hold a struct
+func mayHoldStruct(t reflect.Type, seen map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		// recursive types, ie: type T []T, cannot hold a struct by themselves
+		if seen[t] {
+			return false
+		}
+
+		if seen == nil {
+			seen = make(map[reflect.Type]bool)
+		}
+
+		seen[t] = true
+		return mayHoldStruct(t.Elem(), seen)
+	default:
+		return false
+	}
+}
+
+// hasExportedFields returns true when the given struct type has fields this
+// package can access
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// nested retrieves recursively all types for the given value found at the
+// given path and returns the nested value.
+func (s *Struct) nested(val reflect.Value, path string) any {
+	if value, ok := mapped(val, path); ok {
+		return value
+	}
+
+	if s.config.deep {
+		return s.deep(val, path)
+	}
+
 	var finalVal any
 
 	v := reflect.ValueOf(val.Interface())
@@ -445,17 +909,7 @@ func (s *Struct) nested(val reflect.Value) any {
 
 	switch v.Kind() {
 	case reflect.Struct:
-		n := New(val.Interface())
-		n.TagName = s.TagName
-		m := n.Map()
-
-		// do not add the converted value if there are no exported fields, ie:
-		// time.Time
-		if len(m) == 0 {
-			finalVal = val.Interface()
-		} else {
-			finalVal = m
-		}
+		finalVal, _, _ = s.convert(val.Interface(), path)
 	case reflect.Map:
 		// get the element type of the map
 		mapElem := val.Type()
@@ -475,11 +929,7 @@ func (s *Struct) nested(val reflect.Value) any {
 		if mapElem.Kind() == reflect.Struct ||
 			(mapElem.Kind() == reflect.Slice &&
 				mapElem.Elem().Kind() == reflect.Struct) {
-			m := make(map[string]any, val.Len())
-			for _, k := range val.MapKeys() {
-				m[k.String()] = s.nested(val.MapIndex(k))
-			}
-			finalVal = m
+			finalVal = s.convertMap(val, path, (*Struct).nested)
 			break
 		}
 
@@ -502,9 +952,13 @@ func (s *Struct) nested(val reflect.Value) any {
 			break
 		}
 
-		slices := make([]any, val.Len())
+		slices := make([]any, 0, val.Len())
 		for x := 0; x < val.Len(); x++ {
-			slices[x] = s.nested(val.Index(x))
+			es, ok := s.selectElement(strconv.Itoa(x))
+			if !ok {
+				continue
+			}
+			slices = append(slices, es.nested(val.Index(x), fmt.Sprintf("%s[%d]", path, x)))
 		}
 		finalVal = slices
 	default:
@@ -514,6 +968,71 @@ func (s *Struct) nested(val reflect.Value) any {
 	return finalVal
 }
 
+// stringValue returns the string form of the given value for the "string"
+// option. It is the output of the value's String() func when implemented,
+// otherwise booleans, numbers and strings are formatted with strconv.
+func stringValue(val reflect.Value) (string, bool) {
+	if s, ok := val.Interface().(fmt.Stringer); ok {
+		return s.String(), true
+	}
+
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return "", false
+		}
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(val.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'g', -1, val.Type().Bits()), true
+	case reflect.String:
+		return val.String(), true
+	default:
+		return "", false
+	}
+}
+
+// mapKey returns the string form of the given map key. Strings are kept as
+// they are, keys implementing encoding.TextMarshaler are marshaled, booleans
+// and numbers are formatted with strconv and other keys with fmt.
+func mapKey(k reflect.Value) string {
+	if k.Kind() == reflect.Interface {
+		k = k.Elem()
+	}
+
+	if k.Kind() == reflect.String {
+		return k.String()
+	}
+
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() != reflect.Ptr || !k.IsNil() {
+			if text, err := tm.MarshalText(); err == nil {
+				return string(text)
+			}
+		}
+	}
+
+	switch k.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(k.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(k.Float(), 'g', -1, k.Type().Bits())
+	default:
+		return fmt.Sprint(k.Interface())
+	}
+}
+
 func structVal(s any) reflect.Value {
 	v := reflect.ValueOf(s)
 
@@ -541,12 +1060,58 @@ func FillMap(s any, out map[string]any) {
 	New(s).FillMap(out)
 }
 
-// FillStruct a given struct with the provide map in place.
-// It panics in case of error
-func FillStruct(m map[string]any, s any) {
-	if err := toStruct(m, structVal(s)); err != nil {
+// Entries returns the entries of the given struct in the fields declaration
+// order. For more info refer to Struct types Entries() method. It panics if
+// s's kind is not struct.
+func Entries(s any) []Entry {
+	return New(s).Entries()
+}
+
+// Ordered converts the given struct to an *OrderedMap. For more info refer to
+// Struct types Ordered() method. It panics if s's kind is not struct.
+func Ordered(s any) *OrderedMap {
+	return New(s).Ordered()
+}
+
+// FillStruct a given struct with the provide map in place and returns how
+// the map has been applied. For more info refer to Struct types FillStruct()
+// method. It panics in case of error
+func FillStruct(m map[string]any, s any) DecodeMetadata {
+	meta, err := New(s).FillStruct(m)
+	if err != nil {
 		panic(err)
 	}
+	return meta
+}
+
+// MapErr converts the given struct to a map[string]any and returns the error
+// reported by a Mapper or by the CycleError or CollisionError policies. For
+// more info refer to Struct types MapErr() method. It panics if s's kind is not
+// struct.
+func MapErr(s any) (map[string]any, error) {
+	return New(s).MapErr()
+}
+
+// EntriesErr returns the entries of the given struct and the error reported by
+// a Mapper or by the CycleError or CollisionError policies. For more info refer
+// to Struct types EntriesErr() method. It panics if s's kind is not struct.
+func EntriesErr(s any) ([]Entry, error) {
+	return New(s).EntriesErr()
+}
+
+// OrderedErr converts the given struct to an *OrderedMap and returns the error
+// reported by a Mapper or by the CycleError or CollisionError policies. For
+// more info refer to Struct types OrderedErr() method. It panics if s's kind is
+// not struct.
+func OrderedErr(s any) (*OrderedMap, error) {
+	return New(s).OrderedErr()
+}
+
+// ValuesErr converts the given struct to a []any and returns the error reported
+// by a Mapper or by the CycleError policy. For more info refer to Struct types
+// ValuesErr() method. It panics if s's kind is not struct.
+func ValuesErr(s any) ([]any, error) {
+	return New(s).ValuesErr()
 }
 
 // Values converts the given struct to a []any. For more info refer to
@@ -567,6 +1132,88 @@ func Names(s any) []string {
 	return New(s).Names()
 }
 
+// JSONSchema returns the JSON Schema of the struct type of v, which is either
+// a reflect.Type or a sample value. For more info refer to Config types
+// JSONSchema() method.
+func JSONSchema(v any) ([]byte, error) {
+	return NewConfig().JSONSchema(v)
+}
+
+// TypeScript returns the TypeScript interfaces of the struct types of the
+// given values, which are either reflect.Type or sample values. For more info
+// refer to Config types TypeScript() method.
+func TypeScript(values ...any) ([]byte, error) {
+	return NewConfig().TypeScript(values...)
+}
+
+// Equal reports whether a and b are deeply equal with the given options,
+// along with an explanation of the first difference found. For more info refer
+// to Config types Equal() method.
+func Equal(a, b any, opts ...EqualOption) (bool, string) {
+	return NewConfig().Equal(a, b, opts...)
+}
+
+// Canonical returns the canonical encoding of the given struct. For more info
+// refer to Struct types Canonical() method. It panics if s's kind is not
+// struct.
+func Canonical(s any) ([]byte, error) {
+	return New(s).Canonical()
+}
+
+// Hash writes the canonical encoding of the given struct to the given hash
+// and returns its sum. For more info refer to Struct types Canonical() method.
+// It panics if s's kind is not struct.
+func Hash(s any, h hash.Hash) ([]byte, error) {
+	return New(s).Hash(h)
+}
+
+// LogValue returns the slog.Value of the given struct. For more info refer to
+// Struct types LogValue() method. It panics if s's kind is not struct.
+func LogValue(s any) slog.Value {
+	return New(s).LogValue()
+}
+
+// Redacted returns a map of the given struct with the values of its sensitive
+// fields redacted. For more info refer to Struct types Redacted() method. It
+// panics if s's kind is not struct.
+func Redacted(s any) map[string]any {
+	return New(s).Redacted()
+}
+
+// Redact returns a deep copy of the given struct with the values of its
+// sensitive fields zeroed. For more info refer to Struct types Redact()
+// method. It panics if s's kind is not struct.
+func Redact(s any) any {
+	return New(s).Redact()
+}
+
+// UnknownTagOptions returns the tag options of the given struct which are not
+// understood by this package. For more info refer to Struct types
+// UnknownTagOptions() method. It panics if s's kind is not struct.
+func UnknownTagOptions(s any) []string {
+	return New(s).UnknownTagOptions()
+}
+
+// Reset zeroes every field of the given struct. For more info refer to Struct
+// types Reset() method. It panics if s's kind is not struct.
+func Reset(s any, opts ...ResetOption) error {
+	return New(s).Reset(opts...)
+}
+
+// ZeroFields returns the dotted paths of the fields which are zero. For more
+// info refer to Struct types ZeroFields() method. It panics if s's kind is not
+// struct.
+func ZeroFields(s any) []string {
+	return New(s).ZeroFields()
+}
+
+// NonZeroFields returns the dotted paths of the fields which are not zero. For
+// more info refer to Struct types ZeroFields() method. It panics if s's kind
+// is not struct.
+func NonZeroFields(s any) []string {
+	return New(s).NonZeroFields()
+}
+
 // IsZero returns true if all fields is equal to a zero value. For more info
 // refer to Struct types IsZero() method.  It panics if s's kind is not struct.
 func IsZero(s any) bool {
@@ -600,227 +1247,3 @@ func IsStruct(s any) bool {
 func Name(s any) string {
 	return New(s).Name()
 }
-
-// fromMap sets the given output from the value of a pointer
-func fromPtr(in any, t reflect.Type, out reflect.Value) error {
-	child := reflect.New(t.Elem())
-	if err := fromValue(in, child.Elem(), child.Elem().Type()); err != nil {
-		return err
-	}
-	out.Set(child)
-	return nil
-}
-
-// fromMap sets the given output from a given the elements of a slice
-func fromSlice(in any, out reflect.Value, t reflect.Type) (err error) {
-	input := reflect.ValueOf(in)
-	if input.Kind() != reflect.Slice {
-		return errNotSlice
-	}
-
-	output := reflect.MakeSlice(t, input.Len(), input.Cap())
-	for i := 0; i < input.Len(); i++ {
-		inputValue := reflect.ValueOf(input.Index(i).Interface())
-		elem := reflect.New(output.Index(i).Type()).Elem()
-		if e := fromValue(inputValue.Interface(), elem, elem.Type()); e != nil {
-			err = errors.Join(err, e)
-			continue
-		}
-
-		output.Index(i).Set(elem)
-	}
-
-	if err == nil {
-		out.Set(output)
-	}
-
-	return
-}
-
-// fromMap sets the given output from a given the elements of a map
-func fromMap(in any, out reflect.Value, t reflect.Type) (err error) {
-	input := reflect.ValueOf(in)
-	if input.Kind() != reflect.Map {
-		return errNotMap
-	}
-
-	output := reflect.MakeMap(t)
-	for _, key := range input.MapKeys() {
-		value := reflect.ValueOf(key.Interface())
-		iface := value.Interface()
-		outKey := reflect.New(value.Type()).Elem()
-		if e := fromValue(iface, outKey, outKey.Type()); e != nil {
-			err = errors.Join(err, e)
-			continue
-		}
-
-		inputValue := reflect.ValueOf(input.MapIndex(value).Interface()).Interface()
-		outputValue := reflect.New(output.Type().Elem()).Elem()
-		if e := fromValue(inputValue, outputValue, outputValue.Type()); e != nil {
-			err = errors.Join(err, e)
-			continue
-		}
-
-		output.SetMapIndex(outKey, outputValue)
-	}
-
-	if err == nil {
-		// Special case: out may be a struct or struct pointer...
-		out.Set(output)
-	}
-
-	return
-}
-
-// fromArray sets the given output from a given array or slice elements
-func fromArray(in any, out reflect.Value, t reflect.Type) (err error) {
-	input := reflect.ValueOf(in)
-	if input.Kind() != reflect.Array && input.Kind() != reflect.Slice {
-		return errNotArrayOrSlice
-	}
-
-	output := reflect.New(t).Elem()
-	for i := 0; i < input.Len(); i++ {
-		outputValue := output.Index(i)
-		inputValue := input.Index(i)
-		if e := fromValue(inputValue.Interface(), outputValue, outputValue.Type()); e != nil {
-			err = errors.Join(err, fmt.Errorf("%v:(%s)", e, fmt.Sprintf("@%d", i)))
-			continue
-		}
-	}
-
-	if err == nil {
-		out.Set(output)
-	}
-
-	return
-}
-
-// fromValue set the value of a given input from a given reflected value
-func fromValue(in any, out reflect.Value, t reflect.Type) error {
-	switch out.Kind() {
-	case reflect.Ptr:
-		return fromPtr(in, t, out)
-	case reflect.Struct:
-		return toStruct(in, out)
-	case reflect.Slice:
-		return fromSlice(in, out, t)
-	case reflect.Map:
-		return fromMap(in, out, t)
-	case reflect.Array:
-		return fromArray(in, out, t)
-	default:
-		// pass
-	}
-
-	inputValue := reflect.ValueOf(in)
-	inputType := inputValue.Type()
-	outputType := reflect.ValueOf(out.Interface()).Type()
-
-	if inputType == outputType {
-		// default case: copy the value over
-		out.Set(reflect.ValueOf(in))
-		return nil
-	}
-
-	if inputType.AssignableTo(outputType) {
-		// types are assignable
-		out.Set(inputValue)
-		return nil
-	}
-
-	if inputType.ConvertibleTo(outputType) {
-		// types are convertible
-		out.Set(inputValue.Convert(outputType))
-		return nil
-	}
-
-	return fmt.Errorf("type mismatch: %s and %s are incompatible", outputType.String(), inputType.String())
-}
-
-// toStruct fills a given struct with the provided map values
-func toStruct(in any, s reflect.Value) (err error) {
-	if in == nil {
-		return errors.New("input data is nil")
-	}
-
-	// make sure input is a map
-	if reflect.ValueOf(in).Kind() != reflect.Map {
-		return errNotMap
-	}
-
-	// if target is a pointer to a struct: create a new instance
-	if s.Kind() == reflect.Ptr {
-		s.Set(reflect.New(s.Type().Elem()))
-		s = s.Elem()
-	}
-
-	if s.Kind() != reflect.Struct {
-		return errNotStruct
-	}
-
-	// get the all the exported fields of th passed struct
-	fields := getFields(s, DefaultTagName)
-
-	// Hold the values of the modified fields in a map, which will be applied shortly before
-	// this function returns.
-	// This ensures we do not modify the target struct at all in case of an error
-	modifiedFields := make(map[int]reflect.Value, len(fields))
-	for i, field := range fields {
-		name := field.Name()
-		val := s.FieldByName(name)
-
-		if field.IsEmbedded() {
-			if e := toStruct(in, val); e != nil {
-				err = errors.Join(err, e)
-				continue
-			}
-			continue
-		}
-		// ignore unexported field
-		if !field.IsExported() {
-			continue
-		}
-
-		// handle value struct
-		if field.Kind() == reflect.Struct {
-			if e := toStruct(in, val); e != nil {
-				err = errors.Join(err, e)
-				continue
-			}
-			continue
-		}
-
-		// interfaces are not supported
-		if field.Kind() == reflect.Interface {
-			err = errors.Join(err, fmt.Errorf("interface not supported:(%s)", name))
-			continue
-		}
-
-		// look up value of "fieldName" in map
-		mapVal := reflect.ValueOf(in).MapIndex(reflect.ValueOf(name))
-		if !mapVal.IsValid() {
-			// value not in map, ignore it
-			continue
-		}
-
-		fieldType := val.Type()
-		elem := reflect.New(fieldType).Elem()
-		value := mapVal.Interface()
-		if e := fromValue(value, elem, fieldType); e != nil {
-			err = errors.Join(err, fmt.Errorf("%v:(%s)", e, name))
-			continue
-		}
-
-		modifiedFields[i] = elem
-	}
-
-	// Apply changes to all modified fields in case no error happened during processing.
-	if err == nil {
-		// Apply changes to all modified fields
-		for index, value := range modifiedFields {
-			s.Field(index).Set(value)
-		}
-	}
-	return
-}
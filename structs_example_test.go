@@ -26,6 +26,7 @@
 package structs
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -399,3 +400,49 @@ func ExampleFillStruct() {
 	// Output:
 	// Values: [Arsene 233 false]
 }
+
+func ExampleFillStruct_metadata() {
+	type Server struct {
+		Name    string
+		Port    int `structs:",default=8080"`
+		Enabled bool
+	}
+
+	s := &Server{}
+
+	m := map[string]any{
+		"Name":  "Arsene",
+		"Debug": true,
+	}
+
+	meta := FillStruct(m, s)
+
+	fmt.Printf("Keys: %v\n", meta.Keys)
+	fmt.Printf("Unused: %v\n", meta.Unused)
+	fmt.Printf("Unset: %v\n", meta.Unset)
+	fmt.Printf("Defaults: %v\n", meta.Defaults)
+	// Output:
+	// Keys: [Name]
+	// Unused: [Debug]
+	// Unset: [Enabled]
+	// Defaults: [Port]
+}
+
+func ExampleOrdered() {
+	type Server struct {
+		Name    string `structs:"name"`
+		ID      int32  `structs:"id"`
+		Enabled bool   `structs:"enabled"`
+	}
+
+	s := &Server{
+		Name:    "Arsene",
+		ID:      123456,
+		Enabled: true,
+	}
+
+	data, _ := json.Marshal(Ordered(s))
+	fmt.Println(string(data))
+	// Output:
+	// {"name":"Arsene","id":123456,"enabled":true}
+}
@@ -1566,3 +1566,166 @@ func TestFillNestedStruct(t *testing.T) {
 		t.Error("failed to fill struct")
 	}
 }
+
+func TestMap_FallbackTagNames(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+
+	type User struct {
+		ID       int64    `json:"id,string"`
+		Name     string   `json:"name,omitempty"`
+		Email    string   `structs:"mail" json:"email"`
+		Password string   `json:"-"`
+		Address  Address  `json:"address"`
+		Location *Address `yaml:"location,inline"`
+		Active   bool
+	}
+
+	u := &User{
+		ID:       42,
+		Email:    "gopher@example.com",
+		Password: "secret",
+		Address:  Address{City: "Paris"},
+		Location: &Address{City: "Lyon"},
+		Active:   true,
+	}
+
+	s := New(u, WithFallbackTagNames("json", "yaml"))
+
+	expected := map[string]any{
+		"id":      "42",
+		"mail":    "gopher@example.com",
+		"address": map[string]any{"city": "Paris"},
+		"city":    "Lyon",
+		"Active":  true,
+	}
+
+	if m := s.Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	names := []string{"ID", "Name", "Email", "Address", "Location", "Active"}
+	if n := s.Names(); !reflect.DeepEqual(n, names) {
+		t.Errorf("Names should be %v, got: %v", names, n)
+	}
+}
+
+func TestMap_StringOptionScalar(t *testing.T) {
+	type A struct {
+		Int   int      `structs:",string"`
+		Uint  uint8    `structs:",string"`
+		Float float64  `structs:",string"`
+		Bool  *bool    `structs:",string"`
+		Nil   *float32 `structs:",string"`
+	}
+
+	b := true
+	a := A{Int: -42, Uint: 7, Float: 1.5, Bool: &b}
+
+	expected := map[string]any{"Int": "-42", "Uint": "7", "Float": "1.5", "Bool": "true"}
+	if m := Map(a); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+}
+
+func TestMap_NonStringMapKeys(t *testing.T) {
+	type Item struct {
+		Name string
+	}
+
+	type Pair struct {
+		A, B int
+	}
+
+	type A struct {
+		Ints    map[int]Item
+		Floats  map[float64]Item
+		Bools   map[bool]Item
+		Points  map[Point]Item
+		Structs map[Pair]Item
+	}
+
+	a := A{
+		Ints:    map[int]Item{-1: {Name: "int"}},
+		Floats:  map[float64]Item{1.5: {Name: "float"}},
+		Bools:   map[bool]Item{true: {Name: "bool"}},
+		Points:  map[Point]Item{{X: 1, Y: 2}: {Name: "point"}},
+		Structs: map[Pair]Item{{A: 1, B: 2}: {Name: "pair"}},
+	}
+
+	expected := map[string]any{
+		"Ints":    map[string]any{"-1": map[string]any{"Name": "int"}},
+		"Floats":  map[string]any{"1.5": map[string]any{"Name": "float"}},
+		"Bools":   map[string]any{"true": map[string]any{"Name": "bool"}},
+		"Points":  map[string]any{"1:2": map[string]any{"Name": "point"}},
+		"Structs": map[string]any{"{1 2}": map[string]any{"Name": "pair"}},
+	}
+
+	if m := Map(a); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	kept := New(a, WithMapKeyTypes()).Map()
+
+	ints, ok := kept["Ints"].(map[any]any)
+	if !ok || !reflect.DeepEqual(ints[-1], map[string]any{"Name": "int"}) {
+		t.Errorf("Ints should keep its int keys, got: %#v", kept["Ints"])
+	}
+
+	structs, ok := kept["Structs"].(map[any]any)
+	if !ok || !reflect.DeepEqual(structs[Pair{A: 1, B: 2}], map[string]any{"Name": "pair"}) {
+		t.Errorf("Structs should keep its struct keys, got: %#v", kept["Structs"])
+	}
+}
+
+func TestMap_DeepConversion(t *testing.T) {
+	type Foo struct {
+		Name string
+	}
+
+	type A struct {
+		Items   []any
+		Values  map[string]any
+		Any     any
+		Matrix  [][]Foo
+		Numbers []int
+		Nil     []any
+	}
+
+	a := A{
+		Items:   []any{Foo{Name: "a"}, &Foo{Name: "b"}, 1, []any{Foo{Name: "c"}}},
+		Values:  map[string]any{"x": Foo{Name: "d"}, "y": map[string]any{"z": Foo{Name: "e"}}, "n": nil},
+		Any:     []Foo{{Name: "f"}},
+		Matrix:  [][]Foo{{{Name: "g"}}},
+		Numbers: []int{1, 2},
+	}
+
+	expected := map[string]any{
+		"Items": []any{
+			map[string]any{"Name": "a"},
+			map[string]any{"Name": "b"},
+			1,
+			[]any{map[string]any{"Name": "c"}},
+		},
+		"Values": map[string]any{
+			"x": map[string]any{"Name": "d"},
+			"y": map[string]any{"z": map[string]any{"Name": "e"}},
+			"n": nil,
+		},
+		"Any":     []any{map[string]any{"Name": "f"}},
+		"Matrix":  []any{[]any{map[string]any{"Name": "g"}}},
+		"Numbers": []int{1, 2},
+		"Nil":     []any(nil),
+	}
+
+	if m := New(a, WithDeepConversion()).Map(); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %#v, got: %#v", expected, m)
+	}
+
+	// structs held by interfaces are kept as they are by default
+	m := Map(a)
+	if _, ok := m["Items"].([]any)[0].(Foo); !ok {
+		t.Errorf("Items should be kept as they are by default, got: %#v", m["Items"])
+	}
+}
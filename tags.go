@@ -25,13 +25,48 @@
 
 package structs
 
-import "strings"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
 
-// tagOptions contains a slice of tag options
-type tagOptions []string
+// wellKnownOptions maps the options of well-known tags onto the options of
+// this package
+var wellKnownOptions = map[string]map[string]string{
+	"yaml":         {"inline": "flatten"},
+	"mapstructure": {"squash": "flatten"},
+}
+
+// knownOptions lists the tag options understood by this package
+var knownOptions = map[string]bool{
+	"alias":       true,
+	"default":     true,
+	"description": true,
+	"enum":        true,
+	"flatten":     true,
+	"format":      true,
+	"groups":      true,
+	"keep":        true,
+	"noequal":     true,
+	"nohash":      true,
+	"omitempty":   true,
+	"omitnested":  true,
+	"omitzero":    true,
+	"prefix":      true,
+	"readonly":    true,
+	"required":    true,
+	"sensitive":   true,
+	"string":      true,
+	"writeonly":   true,
+}
 
-// Has returns true if the given option is available in tagOptions
-func (t tagOptions) Has(opt string) bool {
+// TagOptions contains the options of a struct field's tag, which are either
+// flags, ie: "omitempty", or in the form of "option=value", ie: "default=8080"
+type TagOptions []string
+
+// Has returns true if the given option is available in TagOptions
+func (t TagOptions) Has(opt string) bool {
 	for _, tagOpt := range t {
 		if tagOpt == opt {
 			return true
@@ -41,17 +76,153 @@ func (t tagOptions) Has(opt string) bool {
 	return false
 }
 
+// Get returns the value of the given option when it is set in the form of
+// "option=value". The boolean returns whether the option was found.
+func (t TagOptions) Get(opt string) (string, bool) {
+	for _, tagOpt := range t {
+		if value, ok := strings.CutPrefix(tagOpt, opt+"="); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// Unknown returns the names of the options which are not understood by this
+// package, ie: a misspelled "omitempy"
+func (t TagOptions) Unknown() []string {
+	var unknown []string
+	for _, tagOpt := range t {
+		name, _, _ := strings.Cut(tagOpt, "=")
+		if !knownOptions[name] {
+			unknown = append(unknown, name)
+		}
+	}
+
+	return unknown
+}
+
 // parseTag splits a struct field's tag into its name and a list of options
 // which comes after a name. A tag is in the form of: "name,option1,option2".
-// The name can be neglected.
-func parseTag(tag string) (string, tagOptions) {
+// The name can be neglected. Values containing commas are single-quoted and a
+// backslash escapes the next character, ie: "name,layout='Jan 2, 2006'".
+func parseTag(tag string) (string, TagOptions) {
 	// tag is one of followings:
 	// ""
 	// "name"
 	// "name,opt"
 	// "name,opt,opt2"
 	// ",opt"
+	// ",opt=value"
+	// ",opt='value, with comma'"
+
+	var (
+		res     []string
+		current strings.Builder
+		quoted  bool
+		escaped bool
+	)
+
+	for _, r := range tag {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			res = append(res, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
 
-	res := strings.Split(tag, ",")
+	res = append(res, current.String())
 	return res[0], res[1:]
 }
+
+// lookupTag returns the tag of the given field for the first of the given tag
+// names set on the field along with that tag name
+func lookupTag(field reflect.StructField, tagNames []string) (string, string) {
+	for _, tagName := range tagNames {
+		if tag, ok := field.Tag.Lookup(tagName); ok {
+			return tagName, tag
+		}
+	}
+
+	return "", ""
+}
+
+// parseFieldTag returns the name and options of the given field from the
+// first of the given tag names set on the field. The options of well-known
+// tags, such as yaml's "inline", are mapped onto the options of this package.
+func parseFieldTag(field reflect.StructField, tagNames []string) (string, TagOptions) {
+	tagName, tag := lookupTag(field, tagNames)
+	name, opts := parseTag(tag)
+
+	if mapping, ok := wellKnownOptions[tagName]; ok {
+		translated := make(TagOptions, len(opts))
+		for i, opt := range opts {
+			translated[i] = opt
+			if option, ok := mapping[opt]; ok {
+				translated[i] = option
+			}
+		}
+		opts = translated
+	}
+
+	return name, opts
+}
+
+// isIgnored returns true when the given field is ignored with a "-" tag for
+// the first of the given tag names set on the field
+func isIgnored(field reflect.StructField, tagNames []string) bool {
+	_, tag := lookupTag(field, tagNames)
+	return tag == "-"
+}
+
+// UnknownTagOptions returns the tag options of the struct fields, including
+// the fields of the nested struct types, which are not understood by this
+// package. Each of them is reported along with the path of its field, ie:
+// "Database.Port: omitempy". It is meant to lint the struct tags.
+func (s *Struct) UnknownTagOptions() []string {
+	var unknown []string
+	walkTagOptions(s.value.Type(), "", s.tagNames(), make(map[reflect.Type]bool), func(path string, opts TagOptions) {
+		for _, opt := range opts.Unknown() {
+			unknown = append(unknown, fmt.Sprintf("%s: %s", path, opt))
+		}
+	})
+	return unknown
+}
+
+// walkTagOptions calls fn with the path and tag options of every exported
+// field of the struct type t and of the struct types it holds
+func walkTagOptions(t reflect.Type, path string, tagNames []string, seen map[reflect.Type]bool, fn func(string, TagOptions)) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+
+	// recursive types are walked through once
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || isIgnored(field, tagNames) {
+			continue
+		}
+
+		fieldPath := joinPath(path, field.Name)
+		_, opts := parseFieldTag(field, tagNames)
+		fn(fieldPath, opts)
+
+		walkTagOptions(field.Type, fieldPath, tagNames, seen, fn)
+	}
+}
@@ -25,7 +25,10 @@
 
 package structs
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestParseTag_Name(t *testing.T) {
 	tags := []struct {
@@ -69,3 +72,130 @@ func TestParseTag_Opts(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFieldTag(t *testing.T) {
+	type A struct {
+		B string `structs:"b,omitempty" json:"json_b"`
+		C string `json:"c,omitempty"`
+		D string `yaml:"d,inline"`
+		E string `mapstructure:",squash"`
+		F string
+	}
+
+	tagNames := []string{"structs", "json", "yaml", "mapstructure"}
+
+	tests := []struct {
+		field string
+		name  string
+		opts  TagOptions
+	}{
+		{"B", "b", TagOptions{"omitempty"}},
+		{"C", "c", TagOptions{"omitempty"}},
+		{"D", "d", TagOptions{"flatten"}},
+		{"E", "", TagOptions{"flatten"}},
+		{"F", "", TagOptions{}},
+	}
+
+	typ := reflect.TypeOf(A{})
+	for _, test := range tests {
+		field, _ := typ.FieldByName(test.field)
+		name, opts := parseFieldTag(field, tagNames)
+
+		if name != test.name {
+			t.Errorf("Name of field %s should be %q, got: %q", test.field, test.name, name)
+		}
+
+		if !reflect.DeepEqual(opts, test.opts) {
+			t.Errorf("Options of field %s should be %v, got: %v", test.field, test.opts, opts)
+		}
+	}
+}
+
+func TestIsIgnored(t *testing.T) {
+	type A struct {
+		B string `json:"-"`
+		C string `json:"-,"`
+		D string `structs:"d" json:"-"`
+	}
+
+	tagNames := []string{"structs", "json"}
+
+	typ := reflect.TypeOf(A{})
+	for name, ignored := range map[string]bool{"B": true, "C": false, "D": false} {
+		field, _ := typ.FieldByName(name)
+		if isIgnored(field, tagNames) != ignored {
+			t.Errorf("Field %s ignored should be %t", name, ignored)
+		}
+	}
+}
+
+func TestParseTag_Values(t *testing.T) {
+	tests := []struct {
+		tag  string
+		name string
+		opts TagOptions
+	}{
+		{"name,default=8080", "name", TagOptions{"default=8080"}},
+		{",layout='Jan 2, 2006',omitempty", "", TagOptions{"layout=Jan 2, 2006", "omitempty"}},
+		{`name,default=a\,b`, "name", TagOptions{"default=a,b"}},
+		{`name,default='it\'s'`, "name", TagOptions{"default=it's"}},
+		{`'na,me',opt`, "na,me", TagOptions{"opt"}},
+	}
+
+	for _, test := range tests {
+		name, opts := parseTag(test.tag)
+
+		if name != test.name {
+			t.Errorf("Name of tag %q should be %q, got: %q", test.tag, test.name, name)
+		}
+
+		if !reflect.DeepEqual(opts, test.opts) {
+			t.Errorf("Options of tag %q should be %v, got: %v", test.tag, test.opts, opts)
+		}
+	}
+
+	_, opts := parseTag(",layout='Jan 2, 2006'")
+	if layout, ok := opts.Get("layout"); !ok || layout != "Jan 2, 2006" {
+		t.Errorf("Layout should be %q, got: %q", "Jan 2, 2006", layout)
+	}
+}
+
+func TestField_TagOptions(t *testing.T) {
+	type A struct {
+		B int `structs:"b,omitempty,default='1'"`
+		C int `json:"c,string"`
+	}
+
+	s := New(&A{}, WithFallbackTagNames("json"))
+
+	opts := s.Field("B").TagOptions()
+	if !opts.Has("omitempty") {
+		t.Error("Options of B should have omitempty")
+	}
+
+	if def, ok := opts.Get("default"); !ok || def != "1" {
+		t.Errorf("Default of B should be 1, got: %q", def)
+	}
+
+	if !s.Field("C").TagOptions().Has("string") {
+		t.Error("Options of C should be read from the json tag")
+	}
+}
+
+func TestUnknownTagOptions(t *testing.T) {
+	type Database struct {
+		Port int `structs:",omitempy"`
+	}
+
+	type Node struct {
+		Name     string `structs:"name,omitempty,layout=2006"`
+		Database *Database
+		Children []Node `structs:",flaten"`
+		Ignored  string `structs:"-"`
+	}
+
+	expected := []string{"Name: layout", "Database.Port: omitempy", "Children: flaten"}
+	if unknown := UnknownTagOptions(&Node{}); !reflect.DeepEqual(unknown, expected) {
+		t.Errorf("UnknownTagOptions should be %v, got: %v", expected, unknown)
+	}
+}
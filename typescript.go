@@ -0,0 +1,252 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// tsIdentifier matches the property names which do not need quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript returns the TypeScript interfaces describing the output of Map
+// for the struct types of the given values, which are either reflect.Type or
+// sample values. The interfaces of the nested named structs follow the ones of
+// the given types. The properties are resolved like JSONSchema does: the
+// fields Map may omit are optional, the "enum" tag option becomes a union of
+// literals and the "description" tag option a doc comment. Slices become
+// arrays, maps a Record, time.Time a string and the pointers, slices and maps
+// which may be nil accept null. Example:
+//
+//	ts, err := structs.TypeScript(User{}, reflect.TypeOf(Order{}))
+func (c *Config) TypeScript(values ...any) ([]byte, error) {
+	g := &tsGenerator{
+		config: c,
+		names:  newTypeNames(),
+	}
+
+	for _, v := range values {
+		t, ok := v.(reflect.Type)
+		if !ok {
+			t = reflect.TypeOf(v)
+		}
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, errNotStruct
+		}
+
+		g.named(t)
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(g.queue); i++ {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+
+		t := g.queue[i]
+		name, _ := g.names.lookup(t)
+
+		fmt.Fprintf(&buf, "export interface %s ", name)
+		if err := g.object(&buf, t, ""); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// tsGenerator generates the TypeScript interfaces of struct types
+type tsGenerator struct {
+	config *Config
+	names  *typeNames
+	queue  []reflect.Type
+}
+
+// named returns the interface name of the named struct type t, queuing its
+// generation when seen for the first time
+func (g *tsGenerator) named(t reflect.Type) string {
+	name, ok := g.names.lookup(t)
+	if !ok {
+		g.queue = append(g.queue, t)
+	}
+	return name
+}
+
+// object writes the object type of the struct type t, with its properties
+// indented by the given indent
+func (g *tsGenerator) object(buf *bytes.Buffer, t reflect.Type, indent string) error {
+	buf.WriteString("{\n")
+
+	for _, field := range typeFields(g.config, t) {
+		typ, err := g.fieldType(field, indent+"  ")
+		if err != nil {
+			return fmt.Errorf("%v:(%s)", err, joinPath(t.Name(), field.path))
+		}
+
+		if description, ok := field.tagOpts.Get("description"); ok {
+			fmt.Fprintf(buf, "%s  /** %s */\n", indent, strings.ReplaceAll(description, "*/", "*\\/"))
+		}
+
+		key := field.key
+		if !tsIdentifier.MatchString(key) {
+			quoted, _ := json.Marshal(key)
+			key = string(quoted)
+		}
+
+		optional := ""
+		if field.optional {
+			optional = "?"
+		}
+
+		fmt.Fprintf(buf, "%s  %s%s: %s;\n", indent, key, optional, typ)
+	}
+
+	buf.WriteString(indent + "}")
+	return nil
+}
+
+// fieldType returns the type of the given field
+func (g *tsGenerator) fieldType(field typeField, indent string) (string, error) {
+	if enum, ok := field.tagOpts.Get("enum"); ok {
+		var literals []string
+		for _, value := range strings.Split(enum, "|") {
+			parsed, err := parseSchemaValue(value, field.Type)
+			if err != nil {
+				return "", err
+			}
+
+			literal, _ := json.Marshal(parsed)
+			literals = append(literals, string(literal))
+		}
+
+		if field.Type.Kind() == reflect.Ptr {
+			literals = append(literals, "null")
+		}
+		return strings.Join(literals, " | "), nil
+	}
+
+	if field.tagOpts.Has("string") && isScalar(field.Type) {
+		return "string", nil
+	}
+
+	// an empty slice or map is left out with omitempty, hence never null
+	nullable := !field.tagOpts.Has("omitempty") && !g.config.omitEmptyAll
+	if !nullable && (field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map) {
+		return g.elemType(field.Type, indent)
+	}
+
+	return g.typ(field.Type, indent)
+}
+
+// elemType returns the type of the slice or map type t, without null
+func (g *tsGenerator) elemType(t reflect.Type, indent string) (string, error) {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return "string", nil
+	}
+
+	elem, err := g.typ(t.Elem(), indent)
+	if err != nil {
+		return "", err
+	}
+
+	if t.Kind() == reflect.Map {
+		return fmt.Sprintf("Record<string, %s>", elem), nil
+	}
+
+	if strings.Contains(elem, " | ") {
+		elem = "(" + elem + ")"
+	}
+	return elem + "[]", nil
+}
+
+// typ returns the type of the given Go type
+func (g *tsGenerator) typ(t reflect.Type, indent string) (string, error) {
+	switch {
+	case t == timeType:
+		return "string", nil
+	case t == durationType:
+		return "number", nil
+	case t.Implements(mapperType):
+		// the representation is only known at run time
+		return "any", nil
+	case t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler) && t.Kind() != reflect.Ptr:
+		return "string", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Interface:
+		return "any", nil
+	case reflect.Ptr:
+		elem, err := g.typ(t.Elem(), indent)
+		if err != nil || elem == "any" {
+			return elem, err
+		}
+		return elem + " | null", nil
+	case reflect.Slice, reflect.Map:
+		elem, err := g.elemType(t, indent)
+		if err != nil {
+			return "", err
+		}
+		return elem + " | null", nil
+	case reflect.Array:
+		return g.elemType(t, indent)
+	case reflect.Struct:
+		if !hasExportedFields(t) {
+			return "Record<string, never>", nil
+		}
+
+		if t.Name() != "" {
+			return g.named(t), nil
+		}
+
+		var buf bytes.Buffer
+		if err := g.object(&buf, t, indent); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	default:
+		return "", fmt.Errorf("type %s is not supported", t)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypeScript(t *testing.T) {
+	type Meta struct {
+		Labels map[string]string `structs:"labels"`
+		Extra  any               `structs:"extra"`
+	}
+
+	type Order struct {
+		ID       int                  `structs:"id"`
+		User     *schemaUser          `structs:"user"`
+		Items    []*schemaAddress     `structs:"items"`
+		Grid     [2][]int             `structs:"grid"`
+		Data     []byte               `structs:"data,omitempty"`
+		Timeout  time.Duration        `structs:"timeout"`
+		Kind     *string              `structs:"kind,enum=a|b"`
+		Meta     Meta                 `structs:",flatten,prefix=meta_"`
+		Lookup   map[string]*Meta     `structs:"lookup,omitempty"`
+		Weird    string               `structs:"content-type"`
+		Children map[int][]Order      `structs:"children,omitempty"`
+		Ignored  string               `structs:"-"`
+		Nodes    []schemaNode         `structs:"nodes,omitempty"`
+		Empty    struct{ hidden int } `structs:"empty"`
+	}
+
+	got, err := TypeScript(Order{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `export interface Order {
+  id: number;
+  user: schemaUser | null;
+  items: (schemaAddress | null)[] | null;
+  grid: (number[] | null)[];
+  data?: string;
+  timeout: number;
+  kind: "a" | "b" | null;
+  meta_labels: Record<string, string> | null;
+  meta_extra: any;
+  lookup?: Record<string, Meta | null>;
+  "content-type": string;
+  children?: Record<string, Order[] | null>;
+  nodes?: schemaNode[];
+  empty: Record<string, never>;
+}
+
+export interface schemaUser {
+  id: number;
+  /** Full name, as displayed */
+  name: string;
+  email: string;
+  status: "active" | "disabled";
+  level: 1 | 2 | 3;
+  score?: number;
+  tags?: string[];
+  labels?: Record<string, string>;
+  address: schemaAddress;
+  billing?: schemaAddress | null;
+  tree: schemaNode;
+  created: string;
+  count: string;
+  dimension: {
+    width: number;
+  };
+}
+
+export interface schemaAddress {
+  street: string;
+  city?: string;
+}
+
+export interface Meta {
+  labels: Record<string, string> | null;
+  extra: any;
+}
+
+export interface schemaNode {
+  name: string;
+  children?: (schemaNode | null)[];
+}
+`
+
+	if string(got) != want {
+		t.Errorf("TypeScript should be\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestTypeScript_Types(t *testing.T) {
+	type Address struct {
+		City string
+	}
+
+	type User struct {
+		Name    string
+		Address Address
+	}
+
+	config := NewConfig(WithNaming(CamelCase), WithOmitEmptyAll())
+
+	got, err := config.TypeScript(reflect.TypeOf(Address{}), &User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `export interface Address {
+  city?: string;
+}
+
+export interface User {
+  name?: string;
+  address?: Address;
+}
+`
+
+	if string(got) != want {
+		t.Errorf("TypeScript should be\n%s\ngot\n%s", want, got)
+	}
+
+	if _, err := TypeScript(map[string]any{}); err == nil {
+		t.Error("TypeScript should fail for a non struct")
+	}
+
+	type Unsupported struct {
+		Events chan int
+	}
+
+	if _, err := TypeScript(Unsupported{}); err == nil {
+		t.Error("TypeScript should fail for an unsupported field type")
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// zeroer is implemented by the types defining their own zero value, such as
+// time.Time
+type zeroer interface {
+	IsZero() bool
+}
+
+var zeroerType = reflect.TypeOf((*zeroer)(nil)).Elem()
+
+// asZeroer returns the zeroer implementation of the given value, which may be
+// implemented by a pointer to the value when it is addressable
+func asZeroer(val reflect.Value) (zeroer, bool) {
+	if !val.IsValid() || !val.CanInterface() {
+		return nil, false
+	}
+
+	if val.Kind() != reflect.Ptr && val.CanAddr() && val.Addr().Type().Implements(zeroerType) {
+		return val.Addr().Interface().(zeroer), true
+	}
+
+	if !val.Type().Implements(zeroerType) {
+		return nil, false
+	}
+
+	return val.Interface().(zeroer), true
+}
+
+// isZeroValue reports whether the given value is zero, as the "omitzero" option
+// of encoding/json does: its IsZero method is used when it has one.
+func isZeroValue(val reflect.Value) bool {
+	if !val.IsValid() {
+		return true
+	}
+
+	// a nil pointer cannot call a method of its element
+	if (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && val.IsNil() {
+		return true
+	}
+
+	if z, ok := asZeroer(val); ok {
+		return z.IsZero()
+	}
+
+	return val.IsZero()
+}
+
+// isEmptyValue reports whether the given value is empty, as the "omitempty"
+// option of encoding/json does for strings, slices and maps: an empty non-nil
+// slice is empty. The other values are empty when they are zero.
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	default:
+		return isZeroValue(val)
+	}
+}
+
+// hasIsZero returns true when the given value defines its own zero value
+func hasIsZero(val reflect.Value) bool {
+	_, ok := asZeroer(val)
+	return ok
+}
+
+// ZeroFields returns the dotted paths of the fields which are zero, following
+// the rules of IsZero. Nested structs, pointers to structs and the structs held
+// by slices and arrays are walked through, ie: "Database.Host" or
+// "Servers[1].Port", unless their field has the "omitnested" option. A nil
+// pointer or an empty slice is reported as a whole. A struct tag with the
+// content of "-" ignores the field.
+func (s *Struct) ZeroFields() []string {
+	return s.zeroPaths(true)
+}
+
+// NonZeroFields returns the dotted paths of the fields which are not zero. For
+// more info refer to ZeroFields.
+func (s *Struct) NonZeroFields() []string {
+	return s.zeroPaths(false)
+}
+
+// zeroPaths returns the paths of the leaf fields which are zero, or not zero
+func (s *Struct) zeroPaths(zero bool) []string {
+	visited := make(map[visit]bool)
+	if v := reflect.ValueOf(s.raw); v.Kind() == reflect.Ptr {
+		visited[visit{ptr: v.Pointer(), typ: v.Type()}] = true
+	}
+
+	var paths []string
+	s.walkZero("", visited, func(path string, isZero bool) {
+		if isZero == zero {
+			paths = append(paths, path)
+		}
+	})
+	return paths
+}
+
+// walkZero calls report with the path of every leaf field of the struct and
+// whether it is zero. The visited pointers stop the walk on cycles.
+func (s *Struct) walkZero(path string, visited map[visit]bool, report func(string, bool)) {
+	for _, field := range s.structFields() {
+		fieldPath := joinPath(path, field.Name)
+
+		val, ok := s.fieldValue(field)
+		if !ok {
+			// the field is promoted from a nil embedded pointer
+			report(fieldPath, true)
+			continue
+		}
+
+		_, tagOpts := parseFieldTag(field, s.tagNames())
+		if tagOpts.Has("omitnested") {
+			report(fieldPath, isEmptyValue(val))
+			continue
+		}
+
+		s.walkZeroValue(val, fieldPath, visited, report)
+	}
+}
+
+// walkZeroValue walks through the given value found at the given path
+func (s *Struct) walkZeroValue(val reflect.Value, path string, visited map[visit]bool, report func(string, bool)) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() || hasIsZero(val) {
+			report(path, isEmptyValue(val))
+			return
+		}
+
+		if val.Kind() == reflect.Ptr {
+			key := visit{ptr: val.Pointer(), typ: val.Type()}
+			if visited[key] {
+				// the pointer is already being walked through
+				return
+			}
+			visited[key] = true
+			defer delete(visited, key)
+		}
+
+		val = val.Elem()
+	}
+
+	switch {
+	case val.Kind() == reflect.Struct && !hasIsZero(val):
+		s.sub(val.Interface(), path).walkZero(path, visited, report)
+	case (val.Kind() == reflect.Slice || val.Kind() == reflect.Array) && val.Len() > 0 && mayHoldStruct(val.Type().Elem(), nil):
+		for i := 0; i < val.Len(); i++ {
+			s.walkZeroValue(val.Index(i), fmt.Sprintf("%s[%d]", path, i), visited, report)
+		}
+	default:
+		report(path, isEmptyValue(val))
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * The MIT License (MIT)
+ *
+ * Copyright (c) 2014 Fatih Arslan
+ * Copyright (c) 2024 Arsene Tochemey
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package structs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMap_OmitEmptyLength(t *testing.T) {
+	type T struct {
+		Tags   []string          `structs:",omitempty"`
+		Labels map[string]string `structs:",omitempty"`
+		Name   string            `structs:",omitempty"`
+	}
+
+	m := Map(&T{Tags: []string{}, Labels: map[string]string{}})
+	if len(m) != 0 {
+		t.Errorf("Empty slices and maps should be omitted, got: %+v", m)
+	}
+}
+
+func TestMap_OmitZero(t *testing.T) {
+	type T struct {
+		At    time.Time `structs:",omitzero"`
+		Date  Date      `structs:",omitzero"`
+		Tags  []string  `structs:",omitzero"`
+		Count int       `structs:",omitzero"`
+	}
+
+	paris := time.FixedZone("CET", 3600)
+	s := &T{
+		At:   time.Time{}.In(paris),
+		Date: Date{Day: -1},
+		Tags: []string{},
+	}
+
+	expected := map[string]any{"Tags": []string{}}
+	if m := Map(s); !reflect.DeepEqual(m, expected) {
+		t.Errorf("Map should be %+v, got: %+v", expected, m)
+	}
+
+	if values := Values(s); len(values) != 1 {
+		t.Errorf("Values should only hold Tags, got: %+v", values)
+	}
+}
+
+func TestIsZero_Semantics(t *testing.T) {
+	type T struct {
+		At   time.Time
+		Date Date
+		Tags []string
+	}
+
+	s := &T{
+		At:   time.Time{}.In(time.FixedZone("CET", 3600)),
+		Date: Date{Day: -1},
+		Tags: []string{},
+	}
+
+	if !IsZero(s) {
+		t.Error("IsZero should be true")
+	}
+
+	if !New(s).Field("At").IsZero() {
+		t.Error("Field.IsZero should use the IsZero method of time.Time")
+	}
+
+	s.Date.Day = 0
+	if IsZero(s) {
+		t.Error("IsZero should use the IsZero method of the field")
+	}
+
+	if !HasZero(s) {
+		t.Error("HasZero should be true")
+	}
+}
+
+func TestZeroFields(t *testing.T) {
+	type Database struct {
+		Host string
+		Port int
+	}
+
+	type Server struct {
+		Name string
+		Port int
+	}
+
+	type Config struct {
+		Name     string
+		Database Database
+		Replica  *Database
+		Servers  []Server
+		Tags     []string
+		Started  time.Time
+		Raw      Database `structs:",omitnested"`
+		Ignored  string   `structs:"-"`
+	}
+
+	c := &Config{
+		Name:     "app",
+		Database: Database{Host: "localhost"},
+		Servers:  []Server{{Name: "a", Port: 80}, {Name: "b"}},
+		Tags:     []string{},
+		Raw:      Database{Port: 1},
+	}
+
+	expected := []string{"Database.Port", "Replica", "Servers[1].Port", "Tags", "Started"}
+	if zero := ZeroFields(c); !reflect.DeepEqual(zero, expected) {
+		t.Errorf("ZeroFields should be %v, got: %v", expected, zero)
+	}
+
+	expected = []string{"Name", "Database.Host", "Servers[0].Name", "Servers[0].Port", "Servers[1].Name", "Raw"}
+	if nonZero := NonZeroFields(c); !reflect.DeepEqual(nonZero, expected) {
+		t.Errorf("NonZeroFields should be %v, got: %v", expected, nonZero)
+	}
+
+	c.Replica = &Database{Port: 5432}
+	expected = []string{"Database.Port", "Replica.Host", "Servers[1].Port", "Tags", "Started"}
+	if zero := ZeroFields(c); !reflect.DeepEqual(zero, expected) {
+		t.Errorf("ZeroFields should be %v, got: %v", expected, zero)
+	}
+}
+
+func TestZeroFields_Cycle(t *testing.T) {
+	type Node struct {
+		Name     string
+		Parent   *Node
+		Children []*Node
+	}
+
+	root := &Node{Name: "root"}
+	root.Children = []*Node{{Name: "child", Parent: root}}
+
+	expected := []string{"Parent", "Children[0].Children"}
+	if zero := ZeroFields(root); !reflect.DeepEqual(zero, expected) {
+		t.Errorf("ZeroFields should be %v, got: %v", expected, zero)
+	}
+}